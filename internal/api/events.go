@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/watch"
+)
+
+// keepAliveInterval is how often an idle event stream gets a comment, so
+// proxies and clients do not time it out
+const keepAliveInterval = 30 * time.Second
+
+// subscriberBuffer is how many events a slow client may fall behind by
+// before it misses some
+const subscriberBuffer = 64
+
+// Broker fans task change events out to event stream clients
+type Broker struct {
+	mu   sync.Mutex
+	subs map[chan watch.Event]struct{}
+}
+
+// NewBroker creates a broker with no subscribers
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[chan watch.Event]struct{})}
+}
+
+// Publish sends events to every subscriber. It never blocks; a client too
+// far behind misses events.
+func (b *Broker) Publish(events ...watch.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		for _, event := range events {
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+// subscribe registers a new subscriber
+func (b *Broker) subscribe() chan watch.Event {
+	ch := make(chan watch.Event, subscriberBuffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+// unsubscribe removes a subscriber
+func (b *Broker) unsubscribe(ch chan watch.Event) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+}
+
+// streamEvents sends task changes as Server-Sent Events until the client
+// goes away
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok || s.events == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("event stream not available"))
+		return
+	}
+
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event := <-ch:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,340 @@
+// Package api serves strand over a local HTTP/JSON API, for editor
+// plugins, dashboards and scripts in other languages.
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/service"
+	"github.com/hamsa0x7/strand/internal/storage"
+)
+
+// maxBodySize bounds a request body
+const maxBodySize = 1 << 20
+
+// Server handles API requests, running them through a service.Service
+type Server struct {
+	svc    *service.Service
+	events *Broker
+	token  string
+	mux    *http.ServeMux
+}
+
+// NewServer creates a server over svc. events, if not nil, feeds the
+// /api/events stream. A non-empty token must be sent by every request as
+// "Authorization: Bearer <token>".
+func NewServer(svc *service.Service, events *Broker, token string) *Server {
+	s := &Server{svc: svc, events: events, token: token, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /api/tasks", s.listTasks)
+	s.mux.HandleFunc("POST /api/tasks", s.createTask)
+	s.mux.HandleFunc("GET /api/tasks/{id}", s.getTask)
+	s.mux.HandleFunc("PATCH /api/tasks/{id}", s.updateTask)
+	s.mux.HandleFunc("DELETE /api/tasks/{id}", s.deleteTask)
+	s.mux.HandleFunc("GET /api/tasks/{id}/dependencies", s.listDependencies)
+	s.mux.HandleFunc("POST /api/tasks/{id}/dependencies", s.addDependency)
+	s.mux.HandleFunc("DELETE /api/tasks/{id}/dependencies/{dep}", s.removeDependency)
+	s.mux.HandleFunc("GET /api/tasks/{id}/dependents", s.listDependents)
+	s.mux.HandleFunc("GET /api/ready", s.ready)
+	s.mux.HandleFunc("POST /api/claim", s.claim)
+	s.mux.HandleFunc("GET /api/search", s.search)
+	s.mux.HandleFunc("GET /api/events", s.streamEvents)
+	return s
+}
+
+// ServeHTTP checks the bearer token, then routes the request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		auth := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="strand"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, err := intParam(q.Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tasks, err := s.svc.List(service.ListArgs{
+		Where:    q.Get("where"),
+		Status:   core.TaskStatus(q.Get("status")),
+		Assignee: q.Get("assignee"),
+		Tag:      q.Get("tag"),
+		Limit:    limit,
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var args service.CreateArgs
+	if !readJSON(w, r, &args) {
+		return
+	}
+
+	task, err := s.svc.Create(args)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/api/tasks/"+task.ID)
+	writeTask(w, http.StatusCreated, task)
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	task, err := s.svc.Get(service.GetArgs{ID: r.PathValue("id")})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && match == ETag(task.Task) {
+		w.Header().Set("ETag", ETag(task.Task))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeTask(w, http.StatusOK, task)
+}
+
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request) {
+	var args service.UpdateArgs
+	if !readJSON(w, r, &args) {
+		return
+	}
+	args.ID = r.PathValue("id")
+
+	task, err := s.svc.Update(args, ifMatch(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, task)
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	if err := s.svc.Delete(r.PathValue("id"), ifMatch(r)); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listDependencies(w http.ResponseWriter, r *http.Request) {
+	deps, err := s.svc.Dependencies(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deps)
+}
+
+func (s *Server) addDependency(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		DependsOn string `json:"depends_on"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	args := service.DependencyArgs{TaskID: r.PathValue("id"), DependsOn: body.DependsOn}
+	task, err := s.svc.AddDependency(args, ifMatch(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, task)
+}
+
+func (s *Server) removeDependency(w http.ResponseWriter, r *http.Request) {
+	args := service.DependencyArgs{TaskID: r.PathValue("id"), DependsOn: r.PathValue("dep")}
+	task, err := s.svc.RemoveDependency(args, ifMatch(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, task)
+}
+
+func (s *Server) listDependents(w http.ResponseWriter, r *http.Request) {
+	transitive, _ := strconv.ParseBool(r.URL.Query().Get("transitive"))
+	dependents, err := s.svc.Dependents(r.PathValue("id"), transitive)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dependents)
+}
+
+func (s *Server) ready(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, err := intParam(q.Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tasks, err := s.svc.Ready(service.ReadyArgs{
+		Assignee: q.Get("assignee"),
+		Tag:      q.Get("tag"),
+		Limit:    limit,
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *Server) claim(w http.ResponseWriter, r *http.Request) {
+	var args service.ClaimArgs
+	if r.ContentLength != 0 && !readJSON(w, r, &args) {
+		return
+	}
+
+	task, _, err := s.svc.Claim(args)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if task == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("ETag", ETag(task))
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, err := intParam(q.Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	results, err := s.svc.Search(service.SearchArgs{Query: q.Get("q"), Limit: limit})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// ETag identifies a version of a task by the hash of its file, which
+// changes with every write, including edits made outside strand. A task
+// not read from a file falls back to its updated time.
+func ETag(task *core.Task) string {
+	if len(task.Version) >= 16 {
+		return `"` + task.Version[:16] + `"`
+	}
+	return fmt.Sprintf(`"%d"`, task.Updated.Unix())
+}
+
+// errPreconditionFailed is returned when If-Match names another version
+var errPreconditionFailed = errors.New("task has changed since it was read")
+
+// ifMatch checks the task against the request's If-Match header, if any
+func ifMatch(r *http.Request) service.Precondition {
+	match := r.Header.Get("If-Match")
+	if match == "" {
+		return nil
+	}
+
+	return func(current *core.Task) error {
+		if match == "*" {
+			return nil
+		}
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == ETag(current) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: current version is %s", errPreconditionFailed, ETag(current))
+	}
+}
+
+// intParam parses an optional non-negative integer query parameter
+func intParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number: %s", value)
+	}
+	return n, nil
+}
+
+// readJSON decodes the request body into v, writing a 400 response and
+// returning false if it cannot
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if err == io.EOF {
+			err = errors.New("request body is empty")
+		}
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+// writeTask writes a task with its ETag
+func writeTask(w http.ResponseWriter, status int, task service.TaskView) {
+	w.Header().Set("ETag", ETag(task.Task))
+	writeJSON(w, status, task)
+}
+
+// writeJSON writes v as the response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// writeError writes {"error": "..."} with status
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeServiceError maps a service or store error to a status code
+func writeServiceError(w http.ResponseWriter, err error) {
+	var ambiguous *storage.AmbiguousIDError
+	var conflict *storage.ConflictError
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errPreconditionFailed):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, service.ErrInvalid), errors.As(err, &ambiguous):
+		status = http.StatusBadRequest
+	case errors.Is(err, storage.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrConflict), errors.As(err, &conflict):
+		status = http.StatusConflict
+	}
+	writeError(w, status, err)
+}
@@ -2,24 +2,54 @@ package cache
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/hamsa0x7/strand/internal/core"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/hamsa0x7/strand/internal/storage"
+	"github.com/mattn/go-sqlite3"
 )
 
+// driverName is the SQLite driver with strand's SQL functions registered
+const driverName = "sqlite3_strand"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			// SQLite's lower() only folds ASCII; text matches must agree
+			// with the in-memory evaluator, which uses strings.ToLower
+			return conn.RegisterFunc("unicode_lower", strings.ToLower, true)
+		},
+	})
+}
+
 // Cache provides a SQLite-backed cache for fast task queries
 type Cache struct {
-	db *sql.DB
+	db  *sql.DB
+	fts bool // SQLite was built with FTS5 (go build -tags sqlite_fts5)
 }
 
+// schemaVersion is bumped whenever the schema changes; an older cache is
+// dropped and rebuilt from the markdown files
+const schemaVersion = 6
+
+// taskColumns is the column list scanTask expects, in order
+const taskColumns = "id, type, status, priority, title, description, assignee, created, updated, file_path, lease_expires, version, parent, auto_close, due, start, estimate, time_log"
+
 // NewCache creates a new cache instance
 func NewCache(strandDir string) (*Cache, error) {
-	dbPath := filepath.Join(strandDir, ".cache", "tasks.db")
+	cacheDir := filepath.Join(strandDir, ".cache")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	dbPath := filepath.Join(cacheDir, "tasks.db")
 
-	db, err := sql.Open("sqlite3", dbPath)
+	// Several strand processes may share the cache
+	db, err := sql.Open(driverName, dbPath+"?_busy_timeout=5000&_journal_mode=WAL")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open cache database: %w", err)
 	}
@@ -36,6 +66,26 @@ func NewCache(strandDir string) (*Cache, error) {
 
 // initSchema creates the database schema
 func (c *Cache) initSchema() error {
+	var version int
+	if err := c.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version != schemaVersion {
+		drop := `
+		DROP TABLE IF EXISTS tasks;
+		DROP TABLE IF EXISTS task_dependencies;
+		DROP TABLE IF EXISTS task_tags;
+		DROP TABLE IF EXISTS files;
+		DROP TABLE IF EXISTS meta;
+		`
+		if _, err := c.db.Exec(drop); err != nil {
+			return err
+		}
+
+		// Fails harmlessly when this build has no FTS5 module
+		c.db.Exec("DROP TABLE IF EXISTS tasks_fts")
+	}
+
 	schema := `
 	CREATE TABLE IF NOT EXISTS tasks (
 		id TEXT PRIMARY KEY,
@@ -47,7 +97,15 @@ func (c *Cache) initSchema() error {
 		assignee TEXT,
 		created TEXT NOT NULL,
 		updated TEXT NOT NULL,
-		file_path TEXT NOT NULL
+		file_path TEXT NOT NULL,
+		lease_expires TEXT,
+		version TEXT,
+		parent TEXT,
+		auto_close INTEGER NOT NULL DEFAULT 0,
+		due TEXT,
+		start TEXT,
+		estimate INTEGER NOT NULL DEFAULT 0,
+		time_log TEXT
 	);
 	
 	CREATE TABLE IF NOT EXISTS task_dependencies (
@@ -65,13 +123,33 @@ func (c *Cache) initSchema() error {
 		FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
 	);
 	
+	CREATE TABLE IF NOT EXISTS meta (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);
+	
+	CREATE TABLE IF NOT EXISTS files (
+		path TEXT PRIMARY KEY,
+		task_id TEXT NOT NULL,
+		mtime INTEGER NOT NULL,
+		size INTEGER NOT NULL
+	);
+	
 	CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
 	CREATE INDEX IF NOT EXISTS idx_tasks_priority ON tasks(priority);
 	CREATE INDEX IF NOT EXISTS idx_tasks_type ON tasks(type);
+	CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks(parent);
+	CREATE INDEX IF NOT EXISTS idx_tasks_due ON tasks(due);
 	CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags(tag);
 	`
 
-	_, err := c.db.Exec(schema)
+	if _, err := c.db.Exec(schema); err != nil {
+		return err
+	}
+
+	c.fts = c.initFTS()
+
+	_, err := c.db.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion))
 	return err
 }
 
@@ -86,8 +164,8 @@ func (c *Cache) Sync(task *core.Task) error {
 	// Upsert task
 	_, err = tx.Exec(`
 		INSERT OR REPLACE INTO tasks 
-		(id, type, status, priority, title, description, assignee, created, updated, file_path)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		(`+taskColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		task.ID,
 		task.Type,
@@ -96,9 +174,17 @@ func (c *Cache) Sync(task *core.Task) error {
 		task.Title,
 		task.Description,
 		task.Assignee,
-		task.Created.Format(time.RFC3339),
-		task.Updated.Format(time.RFC3339),
+		task.Created.UTC().Format(time.RFC3339),
+		task.Updated.UTC().Format(time.RFC3339),
 		task.FilePath,
+		formatOptionalTime(task.LeaseExpires),
+		task.Version,
+		task.Parent,
+		task.AutoClose,
+		formatDay(task.Due),
+		formatDay(task.Start),
+		int64(task.Estimate),
+		formatTimeLog(task.TimeLog),
 	)
 	if err != nil {
 		return err
@@ -111,7 +197,7 @@ func (c *Cache) Sync(task *core.Task) error {
 	}
 
 	for _, depID := range task.DependsOn {
-		_, err = tx.Exec("INSERT INTO task_dependencies (task_id, depends_on_id) VALUES (?, ?)", task.ID, depID)
+		_, err = tx.Exec("INSERT OR IGNORE INTO task_dependencies (task_id, depends_on_id) VALUES (?, ?)", task.ID, depID)
 		if err != nil {
 			return err
 		}
@@ -124,12 +210,22 @@ func (c *Cache) Sync(task *core.Task) error {
 	}
 
 	for _, tag := range task.Tags {
-		_, err = tx.Exec("INSERT INTO task_tags (task_id, tag) VALUES (?, ?)", task.ID, tag)
+		_, err = tx.Exec("INSERT OR IGNORE INTO task_tags (task_id, tag) VALUES (?, ?)", task.ID, tag)
 		if err != nil {
 			return err
 		}
 	}
 
+	// Refresh the full-text index
+	if c.fts {
+		err = syncFTS(tx, task)
+	} else {
+		err = markFTSStale(tx)
+	}
+	if err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -149,28 +245,37 @@ func (c *Cache) Query(query string, args ...interface{}) ([]*core.Task, error) {
 		}
 		tasks = append(tasks, task)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-	return tasks, rows.Err()
+	return tasks, c.loadRelations(tasks)
 }
 
 // Get retrieves a task by ID from cache
 func (c *Cache) Get(id string) (*core.Task, error) {
-	row := c.db.QueryRow(`
-		SELECT id, type, status, priority, title, description, assignee, created, updated, file_path
-		FROM tasks WHERE id = ?
-	`, id)
+	row := c.db.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE id = ?`, id)
 
-	return c.scanTask(row)
+	task, err := c.scanTask(row)
+	if err != nil {
+		return nil, err
+	}
+	return task, c.loadRelations([]*core.Task{task})
 }
 
-// scanTask scans a database row into a Task
+// scanTask scans a database row into a Task, without its dependencies and
+// tags; see loadRelations. Columns selected after taskColumns are scanned
+// into extra.
 func (c *Cache) scanTask(scanner interface {
 	Scan(dest ...interface{}) error
-}) (*core.Task, error) {
+}, extra ...interface{}) (*core.Task, error) {
 	var task core.Task
 	var created, updated string
+	var lease, version, parent, due, start, timeLog sql.NullString
+	var estimate int64
 
-	err := scanner.Scan(
+	dest := []interface{}{
 		&task.ID,
 		&task.Type,
 		&task.Status,
@@ -181,48 +286,234 @@ func (c *Cache) scanTask(scanner interface {
 		&created,
 		&updated,
 		&task.FilePath,
-	)
+		&lease,
+		&version,
+		&parent,
+		&task.AutoClose,
+		&due,
+		&start,
+		&estimate,
+		&timeLog,
+	}
+
+	err := scanner.Scan(append(dest, extra...)...)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse timestamps
+	// Parse timestamps (stored in UTC so they sort and compare as strings)
 	task.Created, _ = time.Parse(time.RFC3339, created)
 	task.Updated, _ = time.Parse(time.RFC3339, updated)
+	task.Created = task.Created.Local()
+	task.Updated = task.Updated.Local()
+	task.LeaseExpires = parseOptionalTime(lease.String)
+	task.Version = version.String
+	task.Parent = parent.String
+	task.Due = parseDay(due.String)
+	task.Start = parseDay(start.String)
+	task.Estimate = core.Duration(estimate)
+	task.TimeLog = parseTimeLog(timeLog.String)
 
-	// Load dependencies
-	rows, err := c.db.Query("SELECT depends_on_id FROM task_dependencies WHERE task_id = ?", task.ID)
-	if err == nil {
-		defer rows.Close()
-		for rows.Next() {
-			var depID string
-			if rows.Scan(&depID) == nil {
-				task.DependsOn = append(task.DependsOn, depID)
-			}
+	return &task, nil
+}
+
+// relationBatch bounds the IDs bound in one IN (...) list, well under
+// SQLite's variable limit
+const relationBatch = 500
+
+// loadRelations fills in the dependencies and tags of tasks with one query
+// per table for every relationBatch tasks, rather than two per task
+func (c *Cache) loadRelations(tasks []*core.Task) error {
+	for start := 0; start < len(tasks); start += relationBatch {
+		batch := tasks[start:min(start+relationBatch, len(tasks))]
+
+		byID := make(map[string]*core.Task, len(batch))
+		args := make([]interface{}, len(batch))
+		for i, task := range batch {
+			byID[task.ID] = task
+			args[i] = task.ID
 		}
+		in := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", ") + ")"
+
+		err := c.loadRelation(`SELECT task_id, depends_on_id FROM task_dependencies
+			WHERE task_id IN `+in+` ORDER BY task_id, depends_on_id`, args, byID, func(task *core.Task, depID string) {
+			task.DependsOn = append(task.DependsOn, depID)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load dependencies: %w", err)
+		}
+
+		err = c.loadRelation(`SELECT task_id, tag FROM task_tags
+			WHERE task_id IN `+in+` ORDER BY task_id, tag`, args, byID, func(task *core.Task, tag string) {
+			task.Tags = append(task.Tags, tag)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load tags: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadRelation runs a query returning (task_id, value) pairs and adds each
+// value to its task in byID
+func (c *Cache) loadRelation(query string, args []interface{}, byID map[string]*core.Task, add func(task *core.Task, value string)) error {
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return err
 	}
+	defer rows.Close()
 
-	// Load tags
-	rows, err = c.db.Query("SELECT tag FROM task_tags WHERE task_id = ?", task.ID)
-	if err == nil {
-		defer rows.Close()
-		for rows.Next() {
-			var tag string
-			if rows.Scan(&tag) == nil {
-				task.Tags = append(task.Tags, tag)
-			}
+	for rows.Next() {
+		var id, value string
+		if err := rows.Scan(&id, &value); err != nil {
+			return err
+		}
+		if task, ok := byID[id]; ok {
+			add(task, value)
 		}
 	}
+	return rows.Err()
+}
 
-	return &task, nil
+// TagCounts returns how many cached tasks carry each tag, most used first
+func (c *Cache) TagCounts() ([]storage.TagCount, error) {
+	rows, err := c.db.Query(`
+		SELECT tag, COUNT(*) FROM task_tags
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []storage.TagCount{}
+	for rows.Next() {
+		var tc storage.TagCount
+		if err := rows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, tc)
+	}
+	return counts, rows.Err()
+}
+
+// Remove deletes a task and its dependencies and tags from the cache
+func (c *Cache) Remove(id string) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range []string{
+		"DELETE FROM tasks WHERE id = ?",
+		"DELETE FROM task_dependencies WHERE task_id = ?",
+		"DELETE FROM task_tags WHERE task_id = ?",
+	} {
+		if _, err := tx.Exec(stmt, id); err != nil {
+			return err
+		}
+	}
+
+	if c.fts {
+		_, err = tx.Exec("DELETE FROM tasks_fts WHERE id = ?", id)
+	} else {
+		err = markFTSStale(tx)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // Clear removes all cached data
 func (c *Cache) Clear() error {
-	_, err := c.db.Exec("DELETE FROM tasks")
+	_, err := c.db.Exec(`
+		DELETE FROM tasks;
+		DELETE FROM task_dependencies;
+		DELETE FROM task_tags;
+		DELETE FROM files;
+	`)
+	if err != nil {
+		return err
+	}
+
+	if c.fts {
+		_, err = c.db.Exec("DELETE FROM tasks_fts")
+	} else {
+		_, err = c.db.Exec("INSERT OR REPLACE INTO meta (key, value) VALUES ('fts_stale', '1')")
+	}
 	return err
 }
 
+// formatOptionalTime formats t for storage, or "" if unset
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// parseOptionalTime parses a value written by formatOptionalTime
+func parseOptionalTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// formatDay stores an optional calendar day as a UTC timestamp, so query
+// comparisons work as they do for created and updated, or NULL if unset
+func formatDay(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseDay parses a value written by formatDay
+func parseDay(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	t = t.Local()
+	return &t
+}
+
+// formatTimeLog stores work sessions as JSON, or NULL if there are none
+func formatTimeLog(log []core.TimeEntry) interface{} {
+	if len(log) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(log)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
+
+// parseTimeLog parses a value written by formatTimeLog
+func parseTimeLog(s string) []core.TimeEntry {
+	if s == "" {
+		return nil
+	}
+	var log []core.TimeEntry
+	if err := json.Unmarshal([]byte(s), &log); err != nil {
+		return nil
+	}
+	return log
+}
+
 // Close closes the cache database
 func (c *Cache) Close() error {
 	return c.db.Close()
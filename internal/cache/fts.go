@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/storage"
+)
+
+// Snippet highlight markers. Control characters cannot appear in task
+// text, so callers can safely swap them for ANSI styling or markup.
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+// initFTS creates the FTS5 index, reporting whether FTS5 is available.
+//
+// Binaries built without FTS5 can share the cache; they cannot touch the
+// index, so they mark it stale and the next FTS5 build rebuilds it.
+func (c *Cache) initFTS() bool {
+	var existing int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'tasks_fts'").Scan(&existing); err != nil {
+		return false
+	}
+
+	_, err := c.db.Exec(`
+		CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+			id UNINDEXED,
+			title,
+			description,
+			tags,
+			tokenize = 'porter unicode61'
+		)
+	`)
+	if err != nil {
+		return false
+	}
+
+	// An existing table only fails once it is used without the module
+	var rows int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM tasks_fts").Scan(&rows); err != nil {
+		return false
+	}
+
+	var stale string
+	c.db.QueryRow("SELECT value FROM meta WHERE key = 'fts_stale'").Scan(&stale)
+	if existing == 0 || stale != "" {
+		if err := c.rebuildFTS(); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rebuildFTS repopulates the full-text index from the tasks table
+func (c *Cache) rebuildFTS() error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range []string{
+		"DELETE FROM tasks_fts",
+		`INSERT INTO tasks_fts (id, title, description, tags)
+			SELECT id, title, COALESCE(description, ''),
+				COALESCE((SELECT group_concat(tag, ' ') FROM task_tags WHERE task_id = tasks.id), '')
+			FROM tasks`,
+		"DELETE FROM meta WHERE key = 'fts_stale'",
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// markFTSStale records that the index missed a change
+func markFTSStale(tx *sql.Tx) error {
+	_, err := tx.Exec("INSERT OR REPLACE INTO meta (key, value) VALUES ('fts_stale', '1')")
+	return err
+}
+
+// syncFTS replaces a task's row in the full-text index
+func syncFTS(tx *sql.Tx, task *core.Task) error {
+	if _, err := tx.Exec("DELETE FROM tasks_fts WHERE id = ?", task.ID); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(
+		"INSERT INTO tasks_fts (id, title, description, tags) VALUES (?, ?, ?, ?)",
+		task.ID, task.Title, task.Description, strings.Join(task.Tags, " "),
+	)
+	return err
+}
+
+// HasFTS reports whether full-text search is available
+func (c *Cache) HasFTS() bool {
+	return c.fts
+}
+
+// SearchFTS runs a BM25-ranked full-text search. Titles weigh most, then
+// tags, then descriptions. Scores are positive; higher is better.
+func (c *Cache) SearchFTS(text string) ([]*storage.SearchHit, error) {
+	match := ftsQuery(text)
+	if match == "" {
+		return nil, nil
+	}
+
+	cols := "t." + strings.ReplaceAll(taskColumns, ", ", ", t.")
+	rows, err := c.db.Query(`
+		SELECT `+cols+`,
+			-bm25(tasks_fts, 0.0, 10.0, 1.0, 5.0) AS score,
+			snippet(tasks_fts, -1, ?, ?, '…', 12)
+		FROM tasks_fts
+		JOIN tasks t ON t.id = tasks_fts.id
+		WHERE tasks_fts MATCH ?
+		ORDER BY score DESC, t.id
+	`, HighlightStart, HighlightEnd, match)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*storage.SearchHit
+	for rows.Next() {
+		hit := &storage.SearchHit{}
+		task, err := c.scanTask(rows, &hit.Score, &hit.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		hit.Task = task
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	tasks := make([]*core.Task, len(hits))
+	for i, hit := range hits {
+		tasks[i] = hit.Task
+	}
+	return hits, c.loadRelations(tasks)
+}
+
+// ftsQuery turns user input into a safe FTS5 MATCH expression. Quoted
+// phrases stay phrases, a trailing * makes a prefix query, AND/OR/NOT pass
+// through, and every other word is quoted so characters like '-' or ':'
+// in "in-progress" or IDs are not read as FTS5 syntax.
+func ftsQuery(text string) string {
+	var terms []string
+
+	for len(text) > 0 {
+		text = strings.TrimLeft(text, " \t\n")
+		if text == "" {
+			break
+		}
+
+		// Quoted phrase
+		if text[0] == '"' {
+			end := strings.IndexByte(text[1:], '"')
+			var phrase string
+			if end < 0 {
+				phrase, text = text[1:], ""
+			} else {
+				phrase, text = text[1:end+1], text[end+2:]
+			}
+			if phrase = strings.TrimSpace(phrase); phrase != "" {
+				terms = append(terms, quoteFTS(phrase))
+			}
+			continue
+		}
+
+		// Bare word
+		end := strings.IndexAny(text, " \t\n\"")
+		var word string
+		if end < 0 {
+			word, text = text, ""
+		} else {
+			word, text = text[:end], text[end:]
+		}
+
+		switch {
+		case word == "AND" || word == "OR" || word == "NOT":
+			terms = append(terms, word)
+		case strings.HasSuffix(word, "*") && len(word) > 1:
+			terms = append(terms, quoteFTS(strings.TrimSuffix(word, "*"))+"*")
+		default:
+			terms = append(terms, quoteFTS(word))
+		}
+	}
+
+	// Drop dangling operators that would be syntax errors
+	for len(terms) > 0 && isFTSOperator(terms[0]) {
+		terms = terms[1:]
+	}
+	for len(terms) > 0 && isFTSOperator(terms[len(terms)-1]) {
+		terms = terms[:len(terms)-1]
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// quoteFTS quotes a string as an FTS5 phrase
+func quoteFTS(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// isFTSOperator reports whether term is a boolean operator
+func isFTSOperator(term string) bool {
+	return term == "AND" || term == "OR" || term == "NOT"
+}
@@ -0,0 +1,325 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/markdown"
+	"github.com/hamsa0x7/strand/internal/query"
+	"github.com/hamsa0x7/strand/internal/storage"
+)
+
+// Store implements storage.Store on top of the markdown store, serving
+// reads from the SQLite cache. Markdown files stay the source of truth:
+// before every read the cache re-syncs files whose mtime or size changed
+// and drops rows for files that were deleted.
+type Store struct {
+	md    *markdown.Store
+	cache *Cache
+
+	refreshMu sync.Mutex // Watchers may refresh alongside reads
+}
+
+// NewStore creates a new cache-backed store
+func NewStore() *Store {
+	return &Store{md: markdown.NewStore()}
+}
+
+// Init initializes the markdown store and opens the cache
+func (s *Store) Init(strandDir string) error {
+	if err := s.md.Init(strandDir); err != nil {
+		return err
+	}
+
+	cache, err := NewCache(strandDir)
+	if err != nil {
+		return err
+	}
+	s.cache = cache
+
+	return nil
+}
+
+// Create writes the task file and caches it
+func (s *Store) Create(task *core.Task) error {
+	if err := s.md.Create(task); err != nil {
+		return err
+	}
+	return s.syncTask(task)
+}
+
+// Get reads a task from its markdown file, so callers always see the
+// latest content and version for read-modify-write cycles
+func (s *Store) Get(id string) (*core.Task, error) {
+	return s.md.Get(id)
+}
+
+// Resolve expands an ID or unique ID prefix to a full task ID
+func (s *Store) Resolve(prefix string) (string, error) {
+	return s.md.Resolve(prefix)
+}
+
+// List returns all tasks from the cache
+func (s *Store) List() ([]*core.Task, error) {
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+	return s.cache.Query(`SELECT ` + taskColumns + ` FROM tasks ORDER BY id`)
+}
+
+// Update writes the task file and caches it
+func (s *Store) Update(task *core.Task) error {
+	if err := s.md.Update(task); err != nil {
+		return err
+	}
+	return s.syncTask(task)
+}
+
+// Delete removes the task file and its cache rows
+func (s *Store) Delete(id string) error {
+	fullID, err := s.md.Resolve(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.md.Delete(fullID); err != nil {
+		return err
+	}
+
+	if err := s.cache.Remove(fullID); err != nil {
+		return fmt.Errorf("failed to update cache: %w", err)
+	}
+	if _, err := s.cache.db.Exec("DELETE FROM files WHERE task_id = ?", fullID); err != nil {
+		return fmt.Errorf("failed to update cache: %w", err)
+	}
+
+	return nil
+}
+
+// Ready returns tasks with no blocking dependencies
+func (s *Store) Ready() ([]*core.Task, error) {
+	allTasks, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	// Build a map for quick lookup
+	taskMap := make(map[string]*core.Task)
+	for _, t := range allTasks {
+		taskMap[t.ID] = t
+	}
+
+	var readyTasks []*core.Task
+	for _, task := range allTasks {
+		if task.IsReady(taskMap) {
+			readyTasks = append(readyTasks, task)
+		}
+	}
+
+	return readyTasks, nil
+}
+
+// Search runs a ranked full-text search when SQLite has FTS5, and falls
+// back to an unranked substring match on title and description otherwise
+func (s *Store) Search(text string) ([]*storage.SearchHit, error) {
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+
+	if s.cache.HasFTS() {
+		return s.cache.SearchFTS(text)
+	}
+
+	pattern := query.LikePattern(text)
+	tasks, err := s.cache.Query(`
+		SELECT `+taskColumns+` FROM tasks
+		WHERE unicode_lower(title) LIKE ? ESCAPE '\' OR unicode_lower(description) LIKE ? ESCAPE '\'
+		ORDER BY id
+	`, pattern, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*storage.SearchHit, len(tasks))
+	for i, task := range tasks {
+		hits[i] = &storage.SearchHit{Task: task}
+	}
+	return hits, nil
+}
+
+// Where returns tasks matching a query, evaluated in SQLite
+func (s *Store) Where(q *query.Query, env query.Env) ([]*core.Task, error) {
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+
+	where, args := q.SQL(env)
+	return s.cache.Query(`SELECT `+taskColumns+` FROM tasks WHERE `+where+` ORDER BY id`, args...)
+}
+
+// TagCounts returns every tag in use with its task count, from the
+// task_tags table
+func (s *Store) TagCounts() ([]storage.TagCount, error) {
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+	return s.cache.TagCounts()
+}
+
+// Close closes the cache database
+func (s *Store) Close() error {
+	return s.cache.Close()
+}
+
+// Refresh re-syncs task files that changed on disk since they were cached
+// and removes rows for files that no longer exist
+func (s *Store) Refresh() error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	known, err := s.cachedFiles()
+	if err != nil {
+		return fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	entries, err := os.ReadDir(s.md.TasksDir())
+	if err != nil {
+		return fmt.Errorf("failed to read tasks directory: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue // Removed while listing
+		}
+
+		path := filepath.Join(s.md.TasksDir(), entry.Name())
+		seen[path] = true
+
+		if f, ok := known[path]; ok && f.mtime == info.ModTime().UnixNano() && f.size == info.Size() {
+			continue
+		}
+
+		if err := s.syncFile(path, info, known[path].taskID); err != nil {
+			return err
+		}
+	}
+
+	for path, f := range known {
+		if seen[path] {
+			continue
+		}
+		if err := s.forgetFile(path, f.taskID); err != nil {
+			return fmt.Errorf("failed to update cache: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Reindex drops all cached data and rebuilds it from the markdown files
+func (s *Store) Reindex() (int, error) {
+	if err := s.cache.Clear(); err != nil {
+		return 0, fmt.Errorf("failed to clear cache: %w", err)
+	}
+	if err := s.Refresh(); err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := s.cache.db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	return count, nil
+}
+
+// cachedFile is a row of the files table
+type cachedFile struct {
+	taskID string
+	mtime  int64
+	size   int64
+}
+
+// cachedFiles returns the files table keyed by path
+func (s *Store) cachedFiles() (map[string]cachedFile, error) {
+	rows, err := s.cache.db.Query("SELECT path, task_id, mtime, size FROM files")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[string]cachedFile)
+	for rows.Next() {
+		var path string
+		var f cachedFile
+		if err := rows.Scan(&path, &f.taskID, &f.mtime, &f.size); err != nil {
+			return nil, err
+		}
+		files[path] = f
+	}
+
+	return files, rows.Err()
+}
+
+// syncFile parses a changed file and updates its rows. Unparseable files
+// are recorded with an empty task ID so they are not re-read every time.
+func (s *Store) syncFile(path string, info os.FileInfo, oldID string) error {
+	task, err := s.md.Load(path)
+	if err != nil {
+		task = nil
+	}
+
+	// The file may have been rewritten with a different ID, or broken
+	if oldID != "" && (task == nil || task.ID != oldID) {
+		if err := s.cache.Remove(oldID); err != nil {
+			return fmt.Errorf("failed to update cache: %w", err)
+		}
+	}
+
+	taskID := ""
+	if task != nil {
+		if err := s.cache.Sync(task); err != nil {
+			return fmt.Errorf("failed to cache task %s: %w", task.ID, err)
+		}
+		taskID = task.ID
+	}
+
+	_, err = s.cache.db.Exec(`
+		INSERT OR REPLACE INTO files (path, task_id, mtime, size) VALUES (?, ?, ?, ?)
+	`, path, taskID, info.ModTime().UnixNano(), info.Size())
+	if err != nil {
+		return fmt.Errorf("failed to update cache: %w", err)
+	}
+
+	return nil
+}
+
+// forgetFile removes a deleted file and its task from the cache
+func (s *Store) forgetFile(path, taskID string) error {
+	if taskID != "" {
+		if err := s.cache.Remove(taskID); err != nil {
+			return err
+		}
+	}
+	_, err := s.cache.db.Exec("DELETE FROM files WHERE path = ?", path)
+	return err
+}
+
+// syncTask caches a task just written through this store
+func (s *Store) syncTask(task *core.Task) error {
+	info, err := os.Stat(task.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat task file: %w", err)
+	}
+	return s.syncFile(task.FilePath, info, task.ID)
+}
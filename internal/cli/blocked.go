@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// taskView is a task as printed by --json, with its derived status
+type taskView struct {
+	*core.Task
+	EffectiveStatus core.TaskStatus `json:"effective_status"`
+	BlockedBy       []string        `json:"blocked_by,omitempty"`
+}
+
+// viewTasks adds the derived status to tasks, looking dependencies up in
+// taskMap
+func viewTasks(tasks []*core.Task, taskMap map[string]*core.Task) []taskView {
+	views := make([]taskView, len(tasks))
+	for i, task := range tasks {
+		views[i] = taskView{
+			Task:            task,
+			EffectiveStatus: task.EffectiveStatus(taskMap),
+			BlockedBy:       task.OpenDependencies(taskMap),
+		}
+	}
+	return views
+}
+
+// statusLabel renders a task's effective status. A task blocked only by
+// open dependencies is shown as "blocked*".
+func statusLabel(task *core.Task, taskMap map[string]*core.Task) string {
+	if task.IsDependencyBlocked(taskMap) {
+		return string(core.TaskStatusBlocked) + "*"
+	}
+	return string(task.Status)
+}
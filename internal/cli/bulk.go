@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/history"
+	"github.com/hamsa0x7/strand/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bulkWhere  string
+	bulkDryRun bool
+)
+
+// bulkHelp documents how bulk commands pick their tasks
+const bulkHelp = `Pass several IDs, - to read IDs from stdin (one or more per line), or
+--where to act on every matching task. --dry-run shows what would change
+without saving, and --json prints one result per task.`
+
+// bulkResult is the outcome of a bulk command for one task
+type bulkResult struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title,omitempty"`
+	OK      bool     `json:"ok"`
+	Changes []string `json:"changes,omitempty"`
+	Error   string   `json:"error,omitempty"`
+
+	task      *core.Task      // Saved version, nil unless it changed
+	err       error           // The error behind Error
+	followUps []history.Event // Changes it caused to other tasks
+}
+
+// fail marks the result failed with err
+func (r *bulkResult) fail(err error) {
+	r.OK = false
+	r.Error = err.Error()
+	r.err = err
+}
+
+// failedResult is the result for an ID that could not be acted on
+func failedResult(id string, err error) bulkResult {
+	r := bulkResult{ID: id}
+	r.fail(err)
+	return r
+}
+
+// addBulkFlags registers the flags shared by bulk commands
+func addBulkFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&bulkWhere, "where", "w", "", "Act on every task matching this filter expression")
+	cmd.Flags().BoolVar(&bulkDryRun, "dry-run", false, "Show what would change without saving")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output one result per task as JSON")
+}
+
+// bulkTargets returns the task IDs a bulk command acts on: ids, with "-"
+// replaced by the IDs read from stdin, or the tasks matching --where
+func bulkTargets(ids []string) ([]string, error) {
+	if bulkWhere != "" {
+		if len(ids) > 0 {
+			return nil, fmt.Errorf("pass task IDs or --where, not both")
+		}
+		tasks, err := whereTasks(bulkWhere)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tasks: %w", err)
+		}
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("no tasks match --where %s", bulkWhere)
+		}
+		return ids, nil
+	}
+
+	var targets []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			targets = append(targets, id)
+		}
+	}
+	for _, id := range ids {
+		if id != "-" {
+			add(id)
+			continue
+		}
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			for _, field := range strings.Fields(scanner.Text()) {
+				add(field)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read IDs from stdin: %w", err)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no task IDs given")
+	}
+	return targets, nil
+}
+
+// isSingleTarget reports whether a bulk command was given one plain ID,
+// in which case it keeps its detailed single-task output
+func isSingleTarget(args []string) bool {
+	return len(args) == 1 && args[0] != "-" && bulkWhere == "" && !bulkDryRun && !outputJSON
+}
+
+// bulkRun runs op on each task ID with a service that collects the changes
+// it makes to the task into that task's result. With --dry-run the changes
+// are reported but not saved. An invalid argument would fail every task the
+// same way, so it stops the run and is returned on its own.
+func bulkRun(ids []string, op func(svc *service.Service, id string) (*core.Task, error)) ([]bulkResult, error) {
+	results := make([]bulkResult, 0, len(ids))
+
+	for _, id := range ids {
+		result := bulkResult{ID: id}
+		svc := service.New(store, service.Options{
+			Agent:   core.CurrentAgent(),
+			Weights: projectCfg.ScoreWeights(),
+			DryRun:  bulkDryRun,
+			Record: func(events ...history.Event) {
+				if !bulkDryRun {
+					recordEvents(events...)
+				}
+				for _, e := range events {
+					if e.Action == history.ActionAutoClose || e.Action == history.ActionAutoReady {
+						result.followUps = append(result.followUps, e)
+					} else {
+						result.Changes = append(result.Changes, e.String())
+					}
+				}
+			},
+		})
+
+		task, err := op(svc, id)
+		if errors.Is(err, service.ErrInvalid) {
+			return nil, err
+		}
+		if err != nil {
+			result.fail(err)
+			results = append(results, result)
+			continue
+		}
+
+		result.ID, result.Title, result.OK = task.ID, task.Title, true
+		if len(result.Changes) > 0 && !bulkDryRun {
+			result.task = task
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// printFollowUps reports the epics the results auto-closed and the tasks
+// they made ready
+func printFollowUps(results []bulkResult) {
+	if outputJSON {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		for _, e := range r.followUps {
+			if seen[e.Action+e.TaskID] {
+				continue
+			}
+			seen[e.Action+e.TaskID] = true
+			if e.Action == history.ActionAutoClose {
+				fmt.Printf("✅ Auto-closed %s: all children finished\n", e.TaskID)
+			} else if task, err := store.Get(e.TaskID); err == nil {
+				fmt.Printf("✅ Ready: %s (%s), no open dependencies left\n", task.ID, task.Title)
+			}
+		}
+	}
+}
+
+// printBulk reports the results of a bulk command as a table or JSON and
+// returns an error if any task failed. verb is the past tense of the
+// action, e.g. "Updated".
+func printBulk(cmd *cobra.Command, verb string, results []bulkResult) error {
+	succeeded, unchanged, failed := 0, 0, 0
+	for _, r := range results {
+		switch {
+		case !r.OK:
+			failed++
+		case len(r.Changes) == 0:
+			unchanged++
+		default:
+			succeeded++
+		}
+	}
+
+	if outputJSON {
+		report := struct {
+			DryRun    bool         `json:"dry_run"`
+			Succeeded int          `json:"succeeded"`
+			Unchanged int          `json:"unchanged"`
+			Failed    int          `json:"failed"`
+			Results   []bulkResult `json:"results"`
+		}{bulkDryRun, succeeded, unchanged, failed, results}
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		for _, r := range results {
+			switch {
+			case !r.OK:
+				fmt.Printf("❌ %s: %s\n", r.ID, r.Error)
+			case len(r.Changes) == 0:
+				fmt.Printf("   %s: no change\n", r.ID)
+			default:
+				fmt.Printf("✅ %s: %s\n", r.ID, strings.Join(r.Changes, "; "))
+			}
+		}
+
+		fmt.Println()
+		if bulkDryRun {
+			fmt.Printf("Dry run: would have %s %d of %d tasks", strings.ToLower(verb), succeeded, len(results))
+		} else {
+			fmt.Printf("%s %d of %d tasks", verb, succeeded, len(results))
+		}
+		if unchanged > 0 {
+			fmt.Printf(", %d unchanged", unchanged)
+		}
+		if failed > 0 {
+			fmt.Printf(", %d failed", failed)
+		}
+		fmt.Println()
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d tasks failed", failed, len(results))
+	}
+	return nil
+}
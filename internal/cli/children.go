@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+var childrenRecursive bool
+
+var childrenCmd = &cobra.Command{
+	Use:   "children <id>",
+	Short: "List the children of an epic or task",
+	Long: `List the tasks whose parent is the given task, with a progress rollup.
+
+Use --recursive to include grandchildren and deeper descendants.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		task, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+		taskMap := graph.TaskMap(tasks)
+
+		var children []*core.Task
+		if childrenRecursive {
+			children = graph.Descendants(taskMap, task.ID)
+		} else {
+			children = graph.Children(taskMap, task.ID)
+		}
+		rollup := graph.ComputeRollup(taskMap, task.ID)
+
+		if outputJSON {
+			result := struct {
+				Parent   *core.Task    `json:"parent"`
+				Children []*core.Task  `json:"children"`
+				Rollup   *graph.Rollup `json:"rollup,omitempty"`
+			}{
+				Parent:   task,
+				Children: children,
+				Rollup:   rollup,
+			}
+			if result.Children == nil {
+				result.Children = []*core.Task{}
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Task: %s (%s)\n", task.ID, task.Title)
+
+		if len(children) == 0 {
+			fmt.Println("No children")
+			return nil
+		}
+
+		fmt.Println()
+
+		// Table output
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tPRIORITY\tTITLE")
+		fmt.Fprintln(w, "──\t────\t──────\t────────\t─────")
+
+		for _, child := range children {
+			title := child.Title
+			if childrenRecursive {
+				// Indent by depth below the requested task
+				depth := len(graph.Ancestors(taskMap, child.ID)) - len(graph.Ancestors(taskMap, task.ID)) - 1
+				title = strings.Repeat("  ", depth) + title
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				child.ID,
+				child.Type,
+				child.Status,
+				child.Priority,
+				title,
+			)
+		}
+
+		w.Flush()
+		fmt.Println()
+		printRollup(rollup)
+
+		return nil
+	},
+}
+
+// printRollup prints an epic progress summary
+func printRollup(r *graph.Rollup) {
+	if r == nil {
+		return
+	}
+
+	fmt.Printf("Progress:    %d/%d done (%d%%)\n", r.Done, r.Total, r.Percent)
+
+	statuses := make([]string, 0, len(r.ByStatus))
+	for status, count := range r.ByStatus {
+		statuses = append(statuses, fmt.Sprintf("%s: %d", status, count))
+	}
+	sort.Strings(statuses)
+	fmt.Printf("By status:   %s\n", strings.Join(statuses, ", "))
+
+	if len(r.Blocked) > 0 {
+		fmt.Printf("Blocked:     %s\n", strings.Join(r.Blocked, ", "))
+	}
+}
+
+// formatProgress renders a rollup for the list PROGRESS column
+func formatProgress(r *graph.Rollup) string {
+	if r == nil {
+		return ""
+	}
+	progress := fmt.Sprintf("%d/%d %d%%", r.Done, r.Total, r.Percent)
+	if len(r.Blocked) > 0 {
+		progress += fmt.Sprintf(" (%d blocked)", len(r.Blocked))
+	}
+	return progress
+}
+
+func init() {
+	childrenCmd.Flags().BoolVarP(&childrenRecursive, "recursive", "r", false, "Include all descendants")
+	childrenCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+}
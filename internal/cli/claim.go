@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	claimAgent   string
+	claimLease   time.Duration
+	releaseForce bool
+)
+
+// defaultLease is how long a claim lasts without a heartbeat
+const defaultLease = 30 * time.Minute
+
+var claimCmd = &cobra.Command{
+	Use:   "claim",
+	Short: "Claim the best ready task for an agent",
+	Long: `Atomically pick the highest-priority ready task, assign it to the agent,
+move it to the workflow's working status (in-progress by default) and start a
+lease.
+
+The agent must call 'strand heartbeat <id>' before the lease expires.
+Tasks whose lease has expired are returned to the ready pool, so work
+abandoned by a crashed agent is picked up again.
+
+The agent name defaults to $STRAND_AGENT, then $USER.`,
+	Args:        cobra.NoArgs,
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		task, reclaimed, err := cliService(resolveAgent(claimAgent)).Claim(service.ClaimArgs{Lease: claimLease.String()})
+		for _, t := range reclaimed {
+			fmt.Fprintf(os.Stderr, "Reclaimed expired lease on %s (was %s)\n", t.ID, t.Assignee)
+		}
+		if err != nil {
+			return err
+		}
+
+		if task == nil {
+			if outputJSON {
+				fmt.Println("null")
+				return nil
+			}
+			fmt.Println("No tasks available to claim.")
+			return nil
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(task, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("✅ Claimed task: %s\n", task.ID)
+		fmt.Printf("   Title: %s\n", task.Title)
+		fmt.Printf("   Agent: %s\n", task.Assignee)
+		fmt.Printf("   Lease expires: %s\n", task.LeaseExpires.Format("2006-01-02 15:04:05"))
+
+		return nil
+	},
+}
+
+var heartbeatCmd = &cobra.Command{
+	Use:         "heartbeat <id>",
+	Short:       "Extend the lease on a claimed task",
+	Long:        `Renew the lease on a task claimed with 'strand claim' so it is not reclaimed.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		task, err := cliService(resolveAgent(claimAgent)).Heartbeat(service.HeartbeatArgs{
+			ID:    args[0],
+			Lease: claimLease.String(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(task, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("✅ Lease renewed: %s\n", task.ID)
+		fmt.Printf("   Expires: %s\n", task.LeaseExpires.Format("2006-01-02 15:04:05"))
+
+		return nil
+	},
+}
+
+var releaseCmd = &cobra.Command{
+	Use:         "release <id>",
+	Short:       "Release a claimed task",
+	Long:        `Drop the claim on a task, clearing its assignee and returning it to the ready pool.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		task, err := cliService(resolveAgent(claimAgent)).Release(service.ReleaseArgs{
+			ID:    args[0],
+			Force: releaseForce,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Released task: %s\n", task.ID)
+		fmt.Printf("   Status: %s\n", task.Status)
+
+		return nil
+	},
+}
+
+// resolveAgent returns the agent name to act as
+func resolveAgent(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	return core.CurrentAgent()
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{claimCmd, heartbeatCmd, releaseCmd} {
+		cmd.Flags().StringVar(&claimAgent, "agent", "", "Agent name (default $STRAND_AGENT or $USER)")
+	}
+	claimCmd.Flags().DurationVar(&claimLease, "lease", defaultLease, "Lease duration")
+	claimCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	heartbeatCmd.Flags().DurationVar(&claimLease, "lease", defaultLease, "New lease duration from now")
+	heartbeatCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	releaseCmd.Flags().BoolVarP(&releaseForce, "force", "f", false, "Release even if claimed by another agent")
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var commentAuthor string
+
+var commentCmd = &cobra.Command{
+	Use:   "comment <id> <text>",
+	Short: "Add a comment to a task",
+	Long: `Add a comment to a task's discussion without touching its description.
+
+Pass - as the text to read the comment from stdin.`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		body := args[1]
+		if body == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read comment: %w", err)
+			}
+			body = string(data)
+		}
+		if strings.TrimSpace(body) == "" {
+			return fmt.Errorf("comment is empty")
+		}
+
+		task, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		author := resolveAgent(commentAuthor)
+		comment := core.NewComment(author, body)
+		task.AddComment(comment)
+		task.Updated = time.Now()
+
+		if err := store.Update(task); err != nil {
+			return fmt.Errorf("failed to add comment: %w", err)
+		}
+		recordEvents(history.Commented(author, task, comment))
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(comment, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("✅ Commented on task: %s\n", task.ID)
+		fmt.Printf("   Author: %s\n", author)
+		fmt.Printf("   Comments: %d\n", len(task.Comments))
+
+		return nil
+	},
+}
+
+var commentsCmd = &cobra.Command{
+	Use:   "comments <id>",
+	Short: "Show the comments on a task",
+	Long:  `Show a task's discussion, oldest comment first.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		task, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			comments := task.Comments
+			if comments == nil {
+				comments = []core.Comment{}
+			}
+			data, _ := json.MarshalIndent(comments, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Task: %s (%s)\n\n", task.ID, task.Title)
+
+		if len(task.Comments) == 0 {
+			fmt.Println("No comments")
+			return nil
+		}
+
+		printComments(task.Comments)
+
+		return nil
+	},
+}
+
+// printComments prints a discussion thread
+func printComments(comments []core.Comment) {
+	for i, c := range comments {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("── %s, %s\n", c.Author, c.Created.Format("2006-01-02 15:04"))
+		fmt.Println(c.Body)
+	}
+}
+
+func init() {
+	commentCmd.Flags().StringVar(&commentAuthor, "author", "", "Comment author (default $STRAND_AGENT or $USER)")
+	commentCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	commentsCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+}
@@ -5,54 +5,47 @@ import (
 	"fmt"
 
 	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/hamsa0x7/strand/internal/service"
 	"github.com/spf13/cobra"
 )
 
 var (
-	createType     string
-	createPriority string
-	createTags     []string
-	createAssignee string
-	outputJSON     bool
+	createType      string
+	createPriority  string
+	createTags      []string
+	createAssignee  string
+	createParent    string
+	createAutoClose bool
+	createDue       string
+	createStart     string
+	createEstimate  string
+	outputJSON      bool
 )
 
 var createCmd = &cobra.Command{
-	Use:   "create <title>",
-	Short: "Create a new task",
-	Long:  `Create a new task with the given title.`,
-	Args:  cobra.ExactArgs(1),
+	Use:         "create <title>",
+	Short:       "Create a new task",
+	Long:        `Create a new task with the given title.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		title := args[0]
-
-		// Validate inputs
-		if err := ValidateType(createType); err != nil {
+		view, err := cliService(core.CurrentAgent()).Create(service.CreateArgs{
+			Title:     args[0],
+			Type:      core.TaskType(createType),
+			Priority:  core.TaskPriority(createPriority),
+			Tags:      createTags,
+			Assignee:  createAssignee,
+			Parent:    createParent,
+			AutoClose: createAutoClose,
+			Due:       createDue,
+			Start:     createStart,
+			Estimate:  createEstimate,
+		})
+		if err != nil {
 			return err
 		}
-		if createPriority != "" {
-			if err := ValidatePriority(createPriority); err != nil {
-				return err
-			}
-		}
-
-		// Create task
-		taskType := core.TaskType(createType)
-		task := core.NewTask(title, taskType)
-
-		// Apply optional fields
-		if createPriority != "" {
-			task.Priority = core.TaskPriority(createPriority)
-		}
-		if createAssignee != "" {
-			task.Assignee = createAssignee
-		}
-		if len(createTags) > 0 {
-			task.Tags = createTags
-		}
-
-		// Save to storage
-		if err := store.Create(task); err != nil {
-			return fmt.Errorf("failed to create task: %w", err)
-		}
+		task := view.Task
 
 		// Output
 		if outputJSON {
@@ -63,6 +56,12 @@ var createCmd = &cobra.Command{
 			fmt.Printf("   Title: %s\n", task.Title)
 			fmt.Printf("   Type: %s\n", task.Type)
 			fmt.Printf("   Status: %s\n", task.Status)
+			if task.Parent != "" {
+				fmt.Printf("   Parent: %s\n", task.Parent)
+			}
+			if task.Due != nil {
+				fmt.Printf("   Due: %s\n", dates.Format(*task.Due))
+			}
 			fmt.Printf("   File: .strand/tasks/%s.md\n", task.ID)
 		}
 
@@ -75,5 +74,10 @@ func init() {
 	createCmd.Flags().StringVarP(&createPriority, "priority", "p", "medium", "Priority (critical|high|medium|low)")
 	createCmd.Flags().StringSliceVar(&createTags, "tags", []string{}, "Comma-separated tags")
 	createCmd.Flags().StringVarP(&createAssignee, "assignee", "a", "", "Assignee")
+	createCmd.Flags().StringVar(&createParent, "parent", "", "Parent task or epic ID")
+	createCmd.Flags().BoolVar(&createAutoClose, "auto-close", false, "Mark done automatically when all children are done")
+	createCmd.Flags().StringVar(&createDue, "due", "", "Due date ("+dateHelp+")")
+	createCmd.Flags().StringVar(&createStart, "start", "", "Start date; the task is not started before it ("+dateHelp+")")
+	createCmd.Flags().StringVar(&createEstimate, "estimate", "", "Expected effort, e.g. 4h or 1h30m")
 	createCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
 }
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/hamsa0x7/strand/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+var criticalPathCmd = &cobra.Command{
+	Use:   "critical-path [<target-id>]",
+	Short: "Show the longest chain of unfinished work",
+	Long: `Schedule the unfinished work needed to finish a task or epic, or the
+whole project without a target, and show the critical path: the chain of
+tasks that decides when it can be done.
+
+A task's effort is its estimate minus the time logged on it; tasks without
+an estimate count as one unit (1h). A task waits for its dependencies, and
+an epic for its children. START and FINISH are offsets from now assuming
+every task that can run does; SLACK is how long a task can slip without
+delaying the target.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+
+		target := ""
+		if len(args) > 0 {
+			task, err := store.Get(args[0])
+			if err != nil {
+				return err
+			}
+			target = task.ID
+		}
+
+		taskMap := graph.TaskMap(tasks)
+		schedule, err := graph.CriticalPath(taskMap, target)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(schedule, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(schedule.Tasks) == 0 {
+			fmt.Println("No unfinished work.")
+			return nil
+		}
+
+		if target != "" {
+			fmt.Printf("Critical path to %s (%s)\n", target, taskMap[target].Title)
+		} else {
+			fmt.Println("Critical path")
+		}
+		fmt.Printf("  %s\n\n", graph.FormatPath(schedule.Path))
+
+		header := []string{"ID", "EFFORT", "START", "FINISH", "SLACK", "BLOCKS", "STATUS", "TITLE"}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		fmt.Fprintln(w, tableRule(header))
+
+		for _, node := range schedule.Tasks {
+			slack := formatEffortUnits(node.Slack)
+			if node.Critical {
+				slack = "critical"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				node.ID,
+				formatEffortUnits(node.Effort),
+				formatEffortUnits(node.EarliestStart),
+				formatEffortUnits(node.EarliestFinish),
+				slack,
+				node.Blocks,
+				node.Status,
+				node.Title,
+			)
+		}
+
+		w.Flush()
+		fmt.Printf("\nCritical path: %s across %d tasks\n", formatEffortUnits(schedule.Length), len(schedule.Path))
+		fmt.Printf("Remaining effort: %s across %d tasks\n", formatEffortUnits(schedule.Remaining), len(schedule.Tasks))
+
+		if len(schedule.Blockers) > 0 {
+			fmt.Println("\nStart here (critical and unblocked):")
+			for _, id := range schedule.Blockers {
+				task := taskMap[id]
+				fmt.Printf("  %s - %s [%s]\n", task.ID, task.Title, task.Status)
+			}
+		}
+
+		return nil
+	},
+}
+
+// formatEffortUnits renders a schedule duration, e.g. 3h or 1h30m
+func formatEffortUnits(d core.Duration) string {
+	return dates.FormatDuration(time.Duration(d))
+}
+
+func init() {
+	criticalPathCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+}
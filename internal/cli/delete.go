@@ -3,49 +3,104 @@ package cli
 import (
 	"fmt"
 
+	"github.com/hamsa0x7/strand/internal/core"
 	"github.com/spf13/cobra"
 )
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete <id>",
-	Short: "Delete a task",
-	Long:  `Delete a task by its ID. The task file will be permanently removed.`,
-	Args:  cobra.ExactArgs(1),
+	Use:   "delete <id>...",
+	Short: "Delete one or more tasks",
+	Long: `Delete tasks by ID. The task files will be permanently removed.
+
+` + bulkHelp,
+	Args:        cobra.ArbitraryArgs,
+	Annotations: mutating,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		id := args[0]
+		// The confirmation prompt reads stdin too
+		if contains(args, "-") && !forceDelete && !bulkDryRun {
+			return fmt.Errorf("reading IDs from stdin needs --force")
+		}
 
-		// Get task first to show what we're deleting
-		task, err := store.Get(id)
+		ids, err := bulkTargets(args)
 		if err != nil {
 			return err
 		}
 
-		// Confirm deletion (unless --force flag)
-		if !forceDelete {
-			fmt.Printf("⚠️  Delete task '%s'? (y/N): ", task.Title)
-			var response string
-			fmt.Scanln(&response)
+		// Look every task up first to show what we're deleting
+		var tasks []*core.Task
+		var results []bulkResult
+		for _, id := range ids {
+			task, err := store.Get(id)
+			if err != nil {
+				results = append(results, failedResult(id, err))
+				continue
+			}
+			tasks = append(tasks, task)
+		}
 
-			if response != "y" && response != "Y" {
+		if isSingleTarget(args) {
+			if len(tasks) == 0 {
+				return results[0].err
+			}
+			task := tasks[0]
+
+			// Confirm deletion (unless --force flag)
+			if !forceDelete && !confirm(fmt.Sprintf("⚠️  Delete task '%s'? (y/N): ", task.Title)) {
 				fmt.Println("Cancelled.")
 				return nil
 			}
+
+			if err := deleteTask(task); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Deleted task: %s\n", task.ID)
+			fmt.Printf("   Title: %s\n", task.Title)
+			return nil
 		}
 
-		// Delete
-		if err := store.Delete(id); err != nil {
-			return fmt.Errorf("failed to delete task: %w", err)
+		// One confirmation for the whole batch
+		if !forceDelete && !bulkDryRun && len(tasks) > 0 {
+			fmt.Printf("⚠️  About to delete %d tasks:\n", len(tasks))
+			for _, task := range tasks {
+				fmt.Printf("   %s  %s\n", task.ID, task.Title)
+			}
+			if !confirm("Delete them? (y/N): ") {
+				fmt.Println("Cancelled.")
+				return nil
+			}
 		}
 
-		fmt.Printf("✅ Deleted task: %s\n", id)
-		fmt.Printf("   Title: %s\n", task.Title)
+		for _, task := range tasks {
+			result := bulkResult{ID: task.ID, Title: task.Title, OK: true, Changes: []string{"deleted"}}
+			if !bulkDryRun {
+				if err := deleteTask(task); err != nil {
+					result.Changes = nil
+					result.fail(err)
+				}
+			}
+			results = append(results, result)
+		}
 
-		return nil
+		return printBulk(cmd, "Deleted", results)
 	},
 }
 
+// deleteTask removes a task and records it in the history
+func deleteTask(task *core.Task) error {
+	return cliService(core.CurrentAgent()).Delete(task.ID, nil)
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	var response string
+	fmt.Scanln(&response)
+	return response == "y" || response == "Y"
+}
+
 var forceDelete bool
 
 func init() {
 	deleteCmd.Flags().BoolVarP(&forceDelete, "force", "f", false, "Skip confirmation prompt")
+	addBulkFlags(deleteCmd)
 }
@@ -1,9 +1,12 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
-	"time"
 
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/graph"
+	"github.com/hamsa0x7/strand/internal/service"
 	"github.com/spf13/cobra"
 )
 
@@ -14,96 +17,86 @@ var depCmd = &cobra.Command{
 }
 
 var depAddCmd = &cobra.Command{
-	Use:   "add <task-id> <depends-on-id>",
+	Use:   "add <task-id>... <depends-on-id>",
 	Short: "Add a dependency",
-	Long:  `Make a task depend on another task. The task won't be ready until its dependency is done.`,
-	Args:  cobra.ExactArgs(2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		taskID := args[0]
-		dependsOnID := args[1]
+	Long: `Make tasks depend on another task. A task won't be ready until its dependency is done.
 
-		// Get the task
-		task, err := store.Get(taskID)
-		if err != nil {
-			return err
+The last argument is the dependency; every other ID gets it. With --where,
+pass only the dependency. ` + bulkHelp,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if bulkWhere == "" && len(args) < 2 {
+			return fmt.Errorf("pass at least one task ID and the ID it depends on")
 		}
+		taskIDs, dependsOnID := args[:len(args)-1], args[len(args)-1]
 
 		// Verify dependency exists
 		depTask, err := store.Get(dependsOnID)
 		if err != nil {
-			return fmt.Errorf("dependency task not found: %s", dependsOnID)
+			return fmt.Errorf("dependency task not found: %w", err)
 		}
+		dependsOnID = depTask.ID
 
-		// Check if already depends on it
-		for _, dep := range task.DependsOn {
-			if dep == dependsOnID {
-				return fmt.Errorf("task already depends on %s", dependsOnID)
-			}
+		ids, err := bulkTargets(taskIDs)
+		if err != nil {
+			return err
 		}
 
-		// Check for circular dependency (basic check)
-		if dependsOnID == taskID {
-			return fmt.Errorf("cannot create circular dependency: task cannot depend on itself")
+		results, err := bulkRun(ids, func(svc *service.Service, id string) (*core.Task, error) {
+			view, err := svc.AddDependency(service.DependencyArgs{TaskID: id, DependsOn: dependsOnID}, nil)
+			return view.Task, err
+		})
+		if err != nil {
+			return err
 		}
 
-		// TODO: Deep cycle detection
-
-		// Add dependency
-		task.DependsOn = append(task.DependsOn, dependsOnID)
-		task.Updated = time.Now()
-
-		if err := store.Update(task); err != nil {
-			return fmt.Errorf("failed to update task: %w", err)
+		if isSingleTarget(taskIDs) {
+			r := results[0]
+			if !r.OK {
+				return r.err
+			}
+			fmt.Printf("✅ Added dependency\n")
+			fmt.Printf("   Task: %s (%s)\n", r.ID, r.Title)
+			fmt.Printf("   Depends on: %s (%s)\n", depTask.ID, depTask.Title)
+			return nil
 		}
 
-		fmt.Printf("✅ Added dependency\n")
-		fmt.Printf("   Task: %s (%s)\n", task.ID, task.Title)
-		fmt.Printf("   Depends on: %s (%s)\n", depTask.ID, depTask.Title)
-
-		return nil
+		return printBulk(cmd, "Updated", results)
 	},
 }
 
 var depRemoveCmd = &cobra.Command{
-	Use:   "remove <task-id> <depends-on-id>",
-	Short: "Remove a dependency",
-	Long:  `Remove a dependency between tasks.`,
-	Args:  cobra.ExactArgs(2),
+	Use:         "remove <task-id> <depends-on-id>",
+	Short:       "Remove a dependency",
+	Long:        `Remove a dependency between tasks.`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: mutating,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		taskID := args[0]
-		dependsOnID := args[1]
-
-		// Get the task
-		task, err := store.Get(taskID)
+		before, err := store.Get(args[0])
 		if err != nil {
 			return err
 		}
 
-		// Find and remove dependency
-		found := false
-		newDeps := []string{}
-		for _, dep := range task.DependsOn {
-			if dep != dependsOnID {
-				newDeps = append(newDeps, dep)
-			} else {
-				found = true
-			}
-		}
-
-		if !found {
-			return fmt.Errorf("task does not depend on %s", dependsOnID)
+		// The service resolves the dependency against the task's own
+		// list, so references to deleted tasks can still be removed
+		view, err := cliService(core.CurrentAgent()).RemoveDependency(service.DependencyArgs{
+			TaskID:    before.ID,
+			DependsOn: args[1],
+		}, nil)
+		if err != nil {
+			return err
 		}
-
-		task.DependsOn = newDeps
-		task.Updated = time.Now()
-
-		if err := store.Update(task); err != nil {
-			return fmt.Errorf("failed to update task: %w", err)
+		removed := args[1]
+		for _, dep := range before.DependsOn {
+			if !contains(view.DependsOn, dep) {
+				removed = dep
+			}
 		}
 
 		fmt.Printf("✅ Removed dependency\n")
-		fmt.Printf("   Task: %s (%s)\n", task.ID, task.Title)
-		fmt.Printf("   No longer depends on: %s\n", dependsOnID)
+		fmt.Printf("   Task: %s (%s)\n", view.ID, view.Title)
+		fmt.Printf("   No longer depends on: %s\n", removed)
 
 		return nil
 	},
@@ -145,8 +138,150 @@ var depListCmd = &cobra.Command{
 	},
 }
 
+var depTransitive bool
+
+var depDependentsCmd = &cobra.Command{
+	Use:   "dependents <task-id>",
+	Short: "List tasks that depend on a task",
+	Long: `Show the tasks that depend on a task, i.e. what it blocks.
+
+Use --transitive to include tasks that depend on it through other tasks.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		task, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+
+		taskMap := graph.TaskMap(tasks)
+		var dependents []*core.Task
+		if depTransitive {
+			dependents = graph.TransitiveDependents(taskMap, task.ID)
+		} else {
+			dependents = graph.DirectDependents(taskMap, task.ID)
+		}
+
+		if outputJSON {
+			if dependents == nil {
+				dependents = []*core.Task{}
+			}
+			data, _ := json.MarshalIndent(dependents, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Task: %s (%s)\n", task.ID, task.Title)
+
+		if len(dependents) == 0 {
+			fmt.Println("No dependents")
+			return nil
+		}
+
+		if depTransitive {
+			fmt.Printf("\nBlocks %d task(s), directly or transitively:\n\n", len(dependents))
+		} else {
+			fmt.Printf("\nBlocks %d task(s):\n\n", len(dependents))
+		}
+
+		for _, dep := range dependents {
+			fmt.Printf("  - %s (%s) [%s]\n", dep.ID, dep.Title, dep.Status)
+		}
+
+		printImpact(graph.ComputeImpact(taskMap, task.ID))
+
+		return nil
+	},
+}
+
+// printImpact summarizes the open work waiting on a task
+func printImpact(impact graph.Impact) {
+	if impact.Blocks == 0 {
+		return
+	}
+	fmt.Printf("\nCompleting this unblocks %d task(s); %d open task(s) wait on it in total\n",
+		len(impact.Unblocks), impact.Blocks)
+}
+
+var depCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the dependency graph for problems",
+	Long: `Scan every task and report dependency cycles and depends_on references
+to tasks that do not exist. Exits with an error if any problem is found.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+
+		taskMap := graph.TaskMap(tasks)
+		cycles := graph.FindCycles(taskMap)
+		dangling := graph.FindDangling(taskMap)
+
+		if outputJSON {
+			report := struct {
+				Cycles   [][]string          `json:"cycles"`
+				Dangling []graph.DanglingRef `json:"dangling"`
+			}{
+				Cycles:   cycles,
+				Dangling: dangling,
+			}
+			if report.Cycles == nil {
+				report.Cycles = [][]string{}
+			}
+			if report.Dangling == nil {
+				report.Dangling = []graph.DanglingRef{}
+			}
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			if len(cycles) == 0 && len(dangling) == 0 {
+				fmt.Printf("✅ No dependency problems found in %d tasks\n", len(tasks))
+				return nil
+			}
+
+			if len(cycles) > 0 {
+				fmt.Printf("Found %d cycle(s):\n\n", len(cycles))
+				for _, cycle := range cycles {
+					fmt.Printf("  - %s\n", graph.FormatPath(cycle))
+				}
+				fmt.Println()
+			}
+
+			if len(dangling) > 0 {
+				fmt.Printf("Found %d dangling reference(s):\n\n", len(dangling))
+				for _, ref := range dangling {
+					fmt.Printf("  - %s depends on missing task %s\n", ref.TaskID, ref.DependsOn)
+				}
+				fmt.Println()
+			}
+		}
+
+		if problems := len(cycles) + len(dangling); problems > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("found %d dependency problem(s)", problems)
+		}
+
+		return nil
+	},
+}
+
 func init() {
 	depCmd.AddCommand(depAddCmd)
 	depCmd.AddCommand(depRemoveCmd)
 	depCmd.AddCommand(depListCmd)
+	depCmd.AddCommand(depCheckCmd)
+	depCmd.AddCommand(depDependentsCmd)
+
+	addBulkFlags(depAddCmd)
+
+	depDependentsCmd.Flags().BoolVar(&depTransitive, "transitive", false, "Include indirect dependents")
+	depDependentsCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	depCheckCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
 }
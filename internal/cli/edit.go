@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"time"
 
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/history"
+	"github.com/hamsa0x7/strand/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -52,7 +56,38 @@ Falls back to 'notepad' on Windows, 'nano' on Unix.`,
 			return fmt.Errorf("failed to open editor: %w", err)
 		}
 
-		fmt.Println("✅ File saved. Task will be updated on next read.")
+		edited, err := store.Get(task.ID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("edited file is invalid, fix it with 'strand edit %s': %w", task.ID, err)
+		}
+
+		// Pick up the new file now rather than on the next read
+		if refresher, ok := store.(storage.Refresher); ok {
+			if err := refresher.Refresh(); err != nil {
+				return fmt.Errorf("failed to refresh cache: %w", err)
+			}
+		}
+
+		// Record what changed in the editor
+		changes := history.Diff(core.CurrentAgent(), history.ActionEdit, task, edited, time.Now())
+		if len(changes) == 0 {
+			fmt.Println("No changes made.")
+			return nil
+		}
+		// The editor ran without the lock; take it to record and commit
+		release, err := lockProject()
+		if err != nil {
+			return err
+		}
+		recordEvents(changes...)
+		autoCommit()
+		release()
+
+		fmt.Printf("✅ Updated task %s\n", task.ID)
+		for _, e := range changes {
+			fmt.Printf("   %s\n", e)
+		}
 
 		return nil
 	},
@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/hamsa0x7/strand/internal/git"
+	"github.com/hamsa0x7/strand/internal/history"
+	"github.com/hamsa0x7/strand/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitLogLimit int
+	gitLogPatch bool
+)
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Work with the git history of tasks",
+}
+
+var gitLogCmd = &cobra.Command{
+	Use:   "log <id>",
+	Short: "Show the git commits that changed a task",
+	Long: `List the commits that changed a task's file, newest first, including
+edits and merges made outside strand. --patch shows what each one changed.
+Deleted tasks can still be looked up by ID.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repo, err := git.Open(strandDir)
+		if err != nil {
+			return err
+		}
+
+		id, err := store.Resolve(args[0])
+		if errors.Is(err, storage.ErrNotFound) {
+			// The task may have been deleted; look for it in the log
+			id, err = resolveHistoryID(history.Open(strandDir), args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err := repo.Rel(taskPath(id))
+		if err != nil {
+			return err
+		}
+		commits, err := repo.Log(path, gitLogLimit)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			if commits == nil {
+				commits = []git.Commit{}
+			}
+			data, _ := json.MarshalIndent(commits, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(commits) == 0 {
+			fmt.Printf("No commits touch %s.\n", path)
+			return nil
+		}
+
+		if gitLogPatch {
+			for _, c := range commits {
+				patch, err := repo.Show(c.Hash, path)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s  %s  %s\n", shortHash(c.Hash), c.Time.Local().Format("2006-01-02 15:04:05"), c.Author)
+				fmt.Printf("    %s\n\n", c.Subject)
+				fmt.Println(patch)
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "COMMIT\tTIME\tAUTHOR\tSUBJECT")
+		fmt.Fprintln(w, "──────\t────\t──────\t───────")
+		for _, c := range commits {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", shortHash(c.Hash), c.Time.Local().Format("2006-01-02 15:04:05"), c.Author, c.Subject)
+		}
+		return w.Flush()
+	},
+}
+
+// Events recorded since the last auto-commit
+var (
+	commitMu       sync.Mutex
+	pendingCommits []history.Event
+)
+
+// queueCommit remembers events for the next auto-commit, if enabled
+func queueCommit(events ...history.Event) {
+	if projectCfg == nil || !projectCfg.Git.AutoCommit {
+		return
+	}
+	commitMu.Lock()
+	pendingCommits = append(pendingCommits, events...)
+	commitMu.Unlock()
+}
+
+// autoCommit commits the task files changed by the queued events, with a
+// message describing the changes. Problems are reported on stderr, since
+// the changes themselves are already saved.
+func autoCommit() {
+	commitMu.Lock()
+	events := pendingCommits
+	pendingCommits = nil
+	commitMu.Unlock()
+	if len(events) == 0 {
+		return
+	}
+
+	repo, err := git.Open(strandDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: git.auto_commit is on but %v\n", err)
+		return
+	}
+
+	paths := []string{filepath.Join(strandDir, history.FileName)}
+	seen := make(map[string]bool)
+	for _, e := range events {
+		if !seen[e.TaskID] {
+			seen[e.TaskID] = true
+			paths = append(paths, taskPath(e.TaskID))
+		}
+	}
+	for i, path := range paths {
+		if paths[i], err = repo.Rel(path); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to commit task changes: %v\n", err)
+			return
+		}
+	}
+
+	if _, err := repo.CommitPaths(commitMessage(events), paths...); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
+
+// commitMessage describes events as "strand: <action> <id> <changes>".
+// Changes to several tasks get a summary subject and a line per task.
+func commitMessage(events []history.Event) string {
+	var ids []string
+	byTask := make(map[string][]history.Event)
+	for _, e := range events {
+		if _, ok := byTask[e.TaskID]; !ok {
+			ids = append(ids, e.TaskID)
+		}
+		byTask[e.TaskID] = append(byTask[e.TaskID], e)
+	}
+
+	lines := make([]string, len(ids))
+	action := byTask[ids[0]][0].Action
+	for i, id := range ids {
+		lines[i] = describeCommit(id, byTask[id])
+		if byTask[id][0].Action != action {
+			action = history.ActionUpdate
+		}
+	}
+
+	if len(lines) == 1 {
+		return "strand: " + lines[0]
+	}
+	return fmt.Sprintf("strand: %s %d tasks\n\n%s\n", action, len(lines), strings.Join(lines, "\n"))
+}
+
+// describeCommit describes one task's events on one line
+func describeCommit(id string, events []history.Event) string {
+	var changes []string
+	for _, e := range events {
+		switch {
+		case e.Action == history.ActionCreate:
+			changes = append(changes, fmt.Sprintf("%q", e.New))
+		case e.Action == history.ActionDelete:
+			changes = append(changes, fmt.Sprintf("%q", e.Old))
+		case e.Action == history.ActionComment:
+			// The action says it all
+		case e.Field == "description":
+			changes = append(changes, "description")
+		case e.Field == "tags" || e.Field == "depends_on":
+			change := e.Field
+			for _, item := range splitList(e.New) {
+				change += " +" + item
+			}
+			for _, item := range splitList(e.Old) {
+				change += " -" + item
+			}
+			changes = append(changes, change)
+		default:
+			changes = append(changes, fmt.Sprintf("%s %s→%s", e.Field, noneIfEmpty(e.Old), noneIfEmpty(e.New)))
+		}
+	}
+
+	line := events[0].Action + " " + id
+	if len(changes) > 0 {
+		line += " " + strings.Join(changes, ", ")
+	}
+	return line
+}
+
+// taskPath returns the file of a task, whether or not it exists
+func taskPath(id string) string {
+	return filepath.Join(strandDir, "tasks", id+".md")
+}
+
+// shortHash abbreviates a commit hash
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+// splitList splits a history list value ("a, b")
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ", ")
+}
+
+// noneIfEmpty shows an empty value as "none"
+func noneIfEmpty(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func init() {
+	gitLogCmd.Flags().IntVarP(&gitLogLimit, "limit", "n", 0, "Show at most this many commits")
+	gitLogCmd.Flags().BoolVarP(&gitLogPatch, "patch", "p", false, "Show what each commit changed")
+	gitLogCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	gitCmd.AddCommand(gitLogCmd)
+}
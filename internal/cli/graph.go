@@ -1,126 +1,205 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/graph"
 	"github.com/spf13/cobra"
 )
 
+var (
+	graphFormat string
+	graphRoot   string
+	graphStatus []string
+	graphTags   []string
+	graphDepth  int
+)
+
+// graphFormats lists the values accepted by --format
+var graphFormats = []string{"tree", "ascii", "dot", "mermaid", "json"}
+
 var graphCmd = &cobra.Command{
 	Use:   "graph",
 	Short: "Visualize task dependency graph",
-	Long:  `Display a visual representation of task dependencies.`,
+	Long: `Display a visual representation of task dependencies.
+
+Formats:
+  tree     emoji tree, each task under the tasks it depends on (default)
+  ascii    the same tree without emoji
+  dot      Graphviz, e.g. strand graph --format dot | dot -Tsvg > graph.svg
+  mermaid  a Mermaid flowchart for a README
+  json     nodes and edges
+
+--root limits the graph to a task and everything that depends on it, and
+--depth to tasks at most that many dependencies below a root. --status and
+--tag keep only matching tasks.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get all tasks
-		tasks, err := store.List()
-		if err != nil {
-			return fmt.Errorf("failed to list tasks: %w", err)
+		if !contains(graphFormats, graphFormat) {
+			return fmt.Errorf("invalid --format '%s'. Valid: %s", graphFormat, strings.Join(graphFormats, ", "))
 		}
+		return renderGraph(graphFormat)
+	},
+}
 
-		if len(tasks) == 0 {
-			fmt.Println("No tasks found.")
-			return nil
-		}
+// renderGraph selects the filtered graph and prints it in format
+func renderGraph(format string) error {
+	tasks, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
 
-		// Build task map
-		taskMap := make(map[string]*core.Task)
-		for _, task := range tasks {
-			taskMap[task.ID] = task
+	filter := graph.Filter{Tags: graphTags, Depth: graphDepth}
+	if graphRoot != "" {
+		root, err := store.Get(graphRoot)
+		if err != nil {
+			return err
 		}
-
-		// Find root tasks (no dependencies)
-		var roots []*core.Task
-		for _, task := range tasks {
-			if len(task.DependsOn) == 0 {
-				roots = append(roots, task)
-			}
+		filter.Root = root.ID
+	}
+	for _, s := range graphStatus {
+		if err := ValidateStatus(s); err != nil {
+			return err
 		}
+		filter.Statuses = append(filter.Statuses, core.TaskStatus(s))
+	}
 
-		// Print graph
-		fmt.Println("Task Dependency Graph")
-		fmt.Println("=====================")
-		fmt.Println()
+	dag, err := graph.Select(graph.TaskMap(tasks), filter)
+	if err != nil {
+		return err
+	}
 
-		if len(roots) == 0 {
-			fmt.Println("⚠️  All tasks have dependencies (possible circular dependency)")
-			return nil
-		}
+	switch format {
+	case "dot":
+		return graph.WriteDOT(os.Stdout, dag)
+	case "mermaid":
+		return graph.WriteMermaid(os.Stdout, dag)
+	case "json":
+		data, _ := json.MarshalIndent(dag, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
 
-		// Track visited to avoid duplicates
-		visited := make(map[string]bool)
+	if len(dag.Nodes) == 0 {
+		fmt.Println("No tasks found.")
+		return nil
+	}
 
-		for _, root := range roots {
-			printTaskTree(root, taskMap, visited, "", true)
-		}
+	style := emojiTree
+	if format == "ascii" {
+		style = asciiTree
+	}
+	printTree(dag, filter.Root, style)
+	return nil
+}
 
-		// Show orphaned tasks (those that are depended on but we haven't shown)
-		orphans := []*core.Task{}
-		for _, task := range tasks {
-			if !visited[task.ID] {
-				orphans = append(orphans, task)
-			}
-		}
+// treeStyle is how the tree formats draw tasks and branches
+type treeStyle struct {
+	title, warning string
+	branch, last   string
+	pipe           string
+	line           func(task *core.Task, status core.TaskStatus) string
+}
 
-		if len(orphans) > 0 {
-			fmt.Println()
-			fmt.Println("Tasks with dependencies (shown above):")
-			for _, task := range orphans {
-				fmt.Printf("  %s - %s [%s]\n", task.ID, task.Title, task.Status)
-			}
-		}
+var emojiTree = treeStyle{
+	title:   "Task Dependency Graph",
+	warning: "⚠️  Not reachable from a task without dependencies (circular dependency?):",
+	branch:  "├── ",
+	last:    "└── ",
+	pipe:    "│   ",
+	line: func(task *core.Task, status core.TaskStatus) string {
+		return fmt.Sprintf("%s %s - %s [%s]", getStatusIcon(status), getPrioritySymbol(task.Priority), task.Title, status)
+	},
+}
 
-		return nil
+var asciiTree = treeStyle{
+	title:   "Task Dependency Graph (ASCII)",
+	warning: "WARNING: Not reachable from a task without dependencies (circular dependency?):",
+	branch:  "+-- ",
+	last:    "`-- ",
+	pipe:    "|   ",
+	line: func(task *core.Task, status core.TaskStatus) string {
+		return fmt.Sprintf("[%s][%s] %s", getStatusChar(status), getPriorityChar(task.Priority), task.Title)
 	},
 }
 
-func printTaskTree(task *core.Task, taskMap map[string]*core.Task, visited map[string]bool, prefix string, isLast bool) {
-	if visited[task.ID] {
-		return
+// printTree prints each task under the tasks it depends on. A task with
+// several dependencies appears under each of them; after the first time
+// it is marked "(see above)" instead of repeating its subtree. rootID, if
+// set and selected, is the only root.
+func printTree(dag *graph.DAG, rootID string, style treeStyle) {
+	taskMap := graph.TaskMap(dag.Nodes)
+	children := make(map[string][]*core.Task)
+	hasParent := make(map[string]bool)
+	for _, e := range dag.Edges {
+		children[e.From] = append(children[e.From], taskMap[e.To])
+		hasParent[e.To] = true
 	}
-	visited[task.ID] = true
 
-	// Print current task
-	connector := "├── "
-	if isLast {
-		connector = "└── "
+	var roots []*core.Task
+	if root, ok := taskMap[rootID]; ok {
+		roots = []*core.Task{root}
+	} else {
+		for _, task := range dag.Nodes {
+			if !hasParent[task.ID] {
+				roots = append(roots, task)
+			}
+		}
 	}
 
-	statusIcon := getStatusIcon(task.Status)
-	priorityColor := getPrioritySymbol(task.Priority)
+	fmt.Println(style.title)
+	fmt.Println(strings.Repeat("=", len([]rune(style.title))))
+	fmt.Println()
 
-	fmt.Printf("%s%s%s %s - %s [%s]\n",
-		prefix,
-		connector,
-		statusIcon,
-		priorityColor,
-		task.Title,
-		task.Status,
-	)
+	printed := make(map[string]bool)
+	onPath := make(map[string]bool)
 
-	// Prepare prefix for children
-	childPrefix := prefix
-	if isLast {
-		childPrefix += "    "
-	} else {
-		childPrefix += "│   "
-	}
+	var walk func(task *core.Task, prefix string, isLast bool)
+	walk = func(task *core.Task, prefix string, isLast bool) {
+		connector := style.branch
+		if isLast {
+			connector = style.last
+		}
 
-	// Find tasks that depend on this one
-	var dependents []*core.Task
-	for _, t := range taskMap {
-		for _, depID := range t.DependsOn {
-			if depID == task.ID {
-				dependents = append(dependents, t)
-				break
-			}
+		line := style.line(task, dag.Effective[task.ID])
+		if printed[task.ID] || onPath[task.ID] {
+			fmt.Printf("%s%s%s (see above)\n", prefix, connector, line)
+			return
+		}
+		fmt.Printf("%s%s%s\n", prefix, connector, line)
+		printed[task.ID] = true
+		onPath[task.ID] = true
+
+		childPrefix := prefix + style.pipe
+		if isLast {
+			childPrefix = prefix + "    "
 		}
+		for i, child := range children[task.ID] {
+			walk(child, childPrefix, i == len(children[task.ID])-1)
+		}
+		onPath[task.ID] = false
+	}
+
+	for i, root := range roots {
+		walk(root, "", i == len(roots)-1)
 	}
 
-	// Print dependents
-	for i, dep := range dependents {
-		isLastChild := i == len(dependents)-1
-		printTaskTree(dep, taskMap, visited, childPrefix, isLastChild)
+	var unreached []*core.Task
+	for _, task := range dag.Nodes {
+		if !printed[task.ID] {
+			unreached = append(unreached, task)
+		}
+	}
+	if len(unreached) > 0 {
+		fmt.Println()
+		fmt.Println(style.warning)
+		for _, task := range unreached {
+			fmt.Printf("  %s - %s [%s]\n", task.ID, task.Title, dag.Effective[task.ID])
+		}
 	}
 }
 
@@ -162,96 +241,13 @@ func getPrioritySymbol(priority core.TaskPriority) string {
 var graphAsciiCmd = &cobra.Command{
 	Use:   "ascii",
 	Short: "Show ASCII-only dependency graph",
+	Long:  `Same as 'strand graph --format ascii'.`,
+	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get all tasks
-		tasks, err := store.List()
-		if err != nil {
-			return fmt.Errorf("failed to list tasks: %w", err)
-		}
-
-		if len(tasks) == 0 {
-			fmt.Println("No tasks found.")
-			return nil
-		}
-
-		// Build task map
-		taskMap := make(map[string]*core.Task)
-		for _, task := range tasks {
-			taskMap[task.ID] = task
-		}
-
-		// Find root tasks
-		var roots []*core.Task
-		for _, task := range tasks {
-			if len(task.DependsOn) == 0 {
-				roots = append(roots, task)
-			}
-		}
-
-		fmt.Println("Task Dependency Graph (ASCII)")
-		fmt.Println("==============================")
-		fmt.Println()
-
-		if len(roots) == 0 {
-			fmt.Println("WARNING: All tasks have dependencies")
-			return nil
-		}
-
-		visited := make(map[string]bool)
-
-		for _, root := range roots {
-			printTaskTreeAscii(root, taskMap, visited, "", true)
-		}
-
-		return nil
+		return renderGraph("ascii")
 	},
 }
 
-func printTaskTreeAscii(task *core.Task, taskMap map[string]*core.Task, visited map[string]bool, prefix string, isLast bool) {
-	if visited[task.ID] {
-		return
-	}
-	visited[task.ID] = true
-
-	connector := "+-- "
-	if isLast {
-		connector = "`-- "
-	}
-
-	statusChar := getStatusChar(task.Status)
-	priorityChar := getPriorityChar(task.Priority)
-
-	fmt.Printf("%s%s[%s][%s] %s\n",
-		prefix,
-		connector,
-		statusChar,
-		priorityChar,
-		task.Title,
-	)
-
-	childPrefix := prefix
-	if isLast {
-		childPrefix += "    "
-	} else {
-		childPrefix += "|   "
-	}
-
-	var dependents []*core.Task
-	for _, t := range taskMap {
-		for _, depID := range t.DependsOn {
-			if depID == task.ID {
-				dependents = append(dependents, t)
-				break
-			}
-		}
-	}
-
-	for i, dep := range dependents {
-		isLastChild := i == len(dependents)-1
-		printTaskTreeAscii(dep, taskMap, visited, childPrefix, isLastChild)
-	}
-}
-
 func getStatusChar(status core.TaskStatus) string {
 	switch status {
 	case core.TaskStatusDone:
@@ -287,5 +283,10 @@ func getPriorityChar(priority core.TaskPriority) string {
 }
 
 func init() {
+	graphCmd.PersistentFlags().StringVarP(&graphFormat, "format", "f", "tree", "Output format (tree|ascii|dot|mermaid|json)")
+	graphCmd.PersistentFlags().StringVar(&graphRoot, "root", "", "Only this task and the tasks that depend on it")
+	graphCmd.PersistentFlags().StringSliceVar(&graphStatus, "status", nil, "Only tasks with these statuses (comma-separated)")
+	graphCmd.PersistentFlags().StringSliceVar(&graphTags, "tag", nil, "Only tasks with any of these tags (comma-separated)")
+	graphCmd.PersistentFlags().IntVar(&graphDepth, "depth", 0, "Only tasks at most N dependencies below a root (0 = unlimited)")
 	graphCmd.AddCommand(graphAsciiCmd)
 }
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/hamsa0x7/strand/internal/history"
+	"github.com/hamsa0x7/strand/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logSince string
+	logActor string
+	logLimit int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "Show the change history of a task",
+	Long: `Show every recorded change to a task: who changed which field, when,
+and from what to what. Deleted tasks can still be looked up by ID.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := history.Open(strandDir)
+
+		id, err := store.Resolve(args[0])
+		if errors.Is(err, storage.ErrNotFound) {
+			// The task may have been deleted; look for it in the log
+			id, err = resolveHistoryID(log, args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		events, err := log.Read(func(e history.Event) bool {
+			return e.TaskID == id
+		})
+		if err != nil {
+			return err
+		}
+
+		return printEvents(events, false)
+	},
+}
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show recent changes across all tasks",
+	Long: `Show the project's change log, newest last.
+
+--since takes a date (2026-01-02), a time (RFC 3339) or an age (30m, 12h, 7d, 2w).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var since time.Time
+		if logSince != "" {
+			t, err := parseSince(logSince, time.Now())
+			if err != nil {
+				return err
+			}
+			since = t
+		}
+
+		events, err := history.Open(strandDir).Read(func(e history.Event) bool {
+			if !since.IsZero() && e.Time.Before(since) {
+				return false
+			}
+			return logActor == "" || e.Actor == logActor
+		})
+		if err != nil {
+			return err
+		}
+
+		if logLimit > 0 && len(events) > logLimit {
+			events = events[len(events)-logLimit:]
+		}
+
+		return printEvents(events, true)
+	},
+}
+
+// printEvents prints events as a table or JSON
+func printEvents(events []history.Event, showTask bool) error {
+	if outputJSON {
+		if events == nil {
+			events = []history.Event{}
+		}
+		data, _ := json.MarshalIndent(events, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(events) == 0 {
+		fmt.Println("No history recorded.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if showTask {
+		fmt.Fprintln(w, "TIME\tACTOR\tTASK\tACTION\tCHANGE")
+		fmt.Fprintln(w, "────\t─────\t────\t──────\t──────")
+	} else {
+		fmt.Fprintln(w, "TIME\tACTOR\tACTION\tCHANGE")
+		fmt.Fprintln(w, "────\t─────\t──────\t──────")
+	}
+
+	for _, e := range events {
+		when := e.Time.Local().Format("2006-01-02 15:04:05")
+		if showTask {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", when, e.Actor, e.TaskID, e.Action, e.String())
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", when, e.Actor, e.Action, e.String())
+		}
+	}
+
+	return w.Flush()
+}
+
+// resolveHistoryID expands an ID prefix against the task IDs in the log
+func resolveHistoryID(log *history.Log, prefix string) (string, error) {
+	seen := make(map[string]bool)
+	var ids []string
+
+	_, err := log.Read(func(e history.Event) bool {
+		if !seen[e.TaskID] {
+			seen[e.TaskID] = true
+			ids = append(ids, e.TaskID)
+		}
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if seen[prefix] {
+		return prefix, nil
+	}
+
+	var matches []string
+	for _, id := range ids {
+		if core.MatchesIDPrefix(id, prefix) {
+			matches = append(matches, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", storage.ErrNotFound, prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", &storage.AmbiguousIDError{Prefix: prefix, Candidates: matches}
+	}
+}
+
+// parseSince parses a --since value relative to now
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if age, err := dates.ParseAge(value); err == nil {
+		return now.Add(-age), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := dates.Parse(value, now); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since '%s': use a date (2006-01-02, yesterday) or an age (30m, 12h, 7d, 2w)", value)
+}
+
+// recordEvents appends events to the history log. A failure to record is
+// reported but does not undo the change that was already saved.
+func recordEvents(events ...history.Event) {
+	if err := history.Open(strandDir).Append(events...); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	queueCommit(events...)
+}
+
+// recordChanges records the fields that differ between before and after
+func recordChanges(actor, action string, before, after *core.Task) {
+	recordEvents(history.Diff(actor, action, before, after, time.Now())...)
+}
+
+func init() {
+	historyCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	logCmd.Flags().StringVar(&logSince, "since", "", "Only show changes after this date or age (e.g. 2026-01-02, 24h, 7d)")
+	logCmd.Flags().StringVar(&logActor, "actor", "", "Only show changes by this actor")
+	logCmd.Flags().IntVarP(&logLimit, "limit", "n", 0, "Show at most this many of the latest changes")
+	logCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+}
@@ -5,9 +5,12 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/hamsa0x7/strand/internal/config"
 	"github.com/spf13/cobra"
 )
 
+var initGit bool
+
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize a new strand project",
@@ -15,7 +18,12 @@ var initCmd = &cobra.Command{
 	
 This creates:
   .strand/tasks/     - Markdown task files
-  .strand/.cache/    - SQLite cache (future)`,
+  .strand/config.yaml - Workflow statuses, transitions and guards
+  .strand/.cache/    - SQLite cache (rebuilt from tasks/ as needed)
+
+--git also registers strand's merge driver, so git merges task files field
+by field instead of leaving conflict markers in their frontmatter. Git
+config is not cloned: run 'strand init --git' again in each new clone.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get current directory
 		cwd, err := os.Getwd()
@@ -27,7 +35,14 @@ This creates:
 
 		// Check if already initialized
 		if _, err := os.Stat(strandDir); err == nil {
-			return fmt.Errorf("strand project already initialized")
+			if !initGit {
+				return fmt.Errorf("strand project already initialized")
+			}
+			if err := registerMergeDriver(strandDir); err != nil {
+				return err
+			}
+			fmt.Println("✅ Registered the strand merge driver for task files")
+			return nil
 		}
 
 		// Create directory structure
@@ -46,12 +61,25 @@ This creates:
 		gitignore := filepath.Join(strandDir, ".gitignore")
 		gitignoreContent := `.cache/
 *.tmp
+.lock
 `
 		if err := os.WriteFile(gitignore, []byte(gitignoreContent), 0644); err != nil {
 			return fmt.Errorf("failed to create .gitignore: %w", err)
 		}
 
+		// Write the default workflow config
+		configPath := filepath.Join(strandDir, config.FileName)
+		if err := os.WriteFile(configPath, []byte(config.Template), 0644); err != nil {
+			return fmt.Errorf("failed to create %s: %w", config.FileName, err)
+		}
+
 		fmt.Println("✅ Initialized strand project in", strandDir)
+		if initGit {
+			if err := registerMergeDriver(strandDir); err != nil {
+				return err
+			}
+			fmt.Println("✅ Registered the strand merge driver for task files")
+		}
 		fmt.Println()
 		fmt.Println("Next steps:")
 		fmt.Println("  strand create \"My first task\"")
@@ -60,3 +88,7 @@ This creates:
 		return nil
 	},
 }
+
+func init() {
+	initCmd.Flags().BoolVar(&initGit, "git", false, "Register the strand merge driver for task files")
+}
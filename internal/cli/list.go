@@ -4,49 +4,134 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/hamsa0x7/strand/internal/graph"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listWhere string
+	listSort  string
+)
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all tasks",
-	Long:  `List all tasks in the current strand project.`,
+	Long: `List all tasks in the current strand project.
+
+Use --where to filter with a query expression. Fields: id, status, type,
+priority, assignee, tag, dep, parent, title, text, created, updated, due,
+start.
+Operators: ':' '=' '!=' and, for priority and times, '>' '>=' '<' '<='.
+Combine terms with AND, OR, NOT and parentheses; adjacent terms are ANDed.
+
+  assignee:me            the current agent ($STRAND_AGENT or $USER)
+  priority>=high         high or critical
+  updated>7d             updated within the last 7 days
+  created<2026-01-01     created before a date
+  parent:none            top-level tasks only
+  due<+3d                due within the next 3 days (due:none for no date)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		tasks, err := store.List()
+		if err := validateSortKey(listSort); err != nil {
+			return err
+		}
+
+		tasks, err := whereTasks(listWhere)
 		if err != nil {
 			return fmt.Errorf("failed to list tasks: %w", err)
 		}
 
+		if len(tasks) == 0 && listWhere != "" {
+			fmt.Println("No tasks match the filter.")
+			return nil
+		}
+
 		if len(tasks) == 0 {
 			fmt.Println("No tasks found.")
 			fmt.Println("Create one with: strand create \"Task title\"")
 			return nil
 		}
 
+		sortTasks(tasks, listSort)
+
+		// Rollups and blocked statuses need every task, not just the
+		// filtered ones
+		allTasks := tasks
+		if listWhere != "" {
+			if allTasks, err = store.List(); err != nil {
+				return fmt.Errorf("failed to list tasks: %w", err)
+			}
+		}
+		taskMap := graph.TaskMap(allTasks)
+
 		if outputJSON {
-			data, _ := json.MarshalIndent(tasks, "", "  ")
+			data, _ := json.MarshalIndent(viewTasks(tasks, taskMap), "", "  ")
 			fmt.Println(string(data))
 			return nil
 		}
 
+		progress := make(map[string]string)
+		for _, task := range tasks {
+			if p := formatProgress(graph.ComputeRollup(taskMap, task.ID)); p != "" {
+				progress[task.ID] = p
+			}
+		}
+
+		// Optional columns appear only when some task has a value
+		now := time.Now()
+		showDue, showTags := false, false
+		for _, task := range tasks {
+			if task.Due != nil {
+				showDue = true
+			}
+			if len(task.Tags) > 0 {
+				showTags = true
+			}
+		}
+
+		header := []string{"ID", "TYPE", "STATUS", "PRIORITY"}
+		if len(progress) > 0 {
+			header = append(header, "PROGRESS")
+		}
+		if showDue {
+			header = append(header, "DUE")
+		}
+		header = append(header, "TITLE")
+		// Last, so colored tags cannot misalign the table
+		if showTags {
+			header = append(header, "TAGS")
+		}
+
 		// Table output
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tPRIORITY\tTITLE")
-		fmt.Fprintln(w, "──\t────\t──────\t────────\t─────")
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		fmt.Fprintln(w, tableRule(header))
 
+		derived := false
 		for _, task := range tasks {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-				task.ID,
-				task.Type,
-				task.Status,
-				task.Priority,
-				task.Title,
-			)
+			status := statusLabel(task, taskMap)
+			derived = derived || task.IsDependencyBlocked(taskMap)
+			row := []string{task.ID, string(task.Type), status, string(task.Priority)}
+			if len(progress) > 0 {
+				row = append(row, progress[task.ID])
+			}
+			if showDue {
+				row = append(row, formatDue(task, now))
+			}
+			row = append(row, task.Title)
+			if showTags {
+				row = append(row, tagLabels(task.Tags))
+			}
+			fmt.Fprintln(w, strings.Join(row, "\t"))
 		}
 
 		w.Flush()
+		if derived {
+			fmt.Println("\n* blocked by open dependencies")
+		}
 		fmt.Printf("\nTotal: %d tasks\n", len(tasks))
 
 		return nil
@@ -55,4 +140,6 @@ var listCmd = &cobra.Command{
 
 func init() {
 	listCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	listCmd.Flags().StringVarP(&listWhere, "where", "w", "", whereHelp)
+	listCmd.Flags().StringVar(&listSort, "sort", "id", "Sort by "+strings.Join(sortKeys, ", "))
 }
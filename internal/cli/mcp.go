@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hamsa0x7/strand/internal/mcp"
+	"github.com/hamsa0x7/strand/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var mcpAgent string
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve tasks to AI agents over the Model Context Protocol",
+	Long: `Run an MCP server on stdin/stdout, so agents can list, create, update,
+claim and search tasks through tool calls, and read task files as resources.
+
+Register it with an MCP client, e.g.:
+
+  {"mcpServers": {"strand": {"command": "strand", "args": ["mcp"], "cwd": "/path/to/project"}}}
+
+Clients that subscribe to a task resource are notified when its file
+changes, whoever changes it. Each write takes the project lock, so the
+server can run alongside the CLI and other agents. Changes are recorded in
+the history as --agent (default $STRAND_AGENT, then $USER).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server := newMCPServer()
+
+		// Tell the client about changes to task files, whoever makes them
+		watcher, err := newWatcher(0)
+		if err != nil {
+			return err
+		}
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		go watcher.Run(ctx, func(events []watch.Event) { server.TasksChanged(events) })
+
+		return server.Serve(os.Stdin, os.Stdout)
+	},
+}
+
+var mcpCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check that the MCP server starts and answers",
+	Long:  `Run the MCP server in-process, perform the handshake and list its tools and resources, without changing any task.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		h := mcp.NewHarness(newMCPServer())
+
+		version, err := h.Initialize()
+		if err != nil {
+			h.Close()
+			return fmt.Errorf("handshake failed: %w", err)
+		}
+		tools, err := h.ListTools()
+		if err != nil {
+			h.Close()
+			return fmt.Errorf("tools/list failed: %w", err)
+		}
+		resources, err := h.ListResources()
+		if err != nil {
+			h.Close()
+			return fmt.Errorf("resources/list failed: %w", err)
+		}
+		result, err := h.CallTool("list_tasks", map[string]int{"limit": 1})
+		if err != nil {
+			h.Close()
+			return fmt.Errorf("list_tasks failed: %w", err)
+		}
+		if result.IsError {
+			h.Close()
+			return fmt.Errorf("list_tasks failed: %s", result.Content[0].Text)
+		}
+		if err := h.Close(); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ MCP server OK (protocol %s)\n", version)
+		fmt.Printf("   Tools (%d):", len(tools))
+		for _, t := range tools {
+			fmt.Printf(" %s", t.Name)
+		}
+		fmt.Println()
+		fmt.Printf("   Resources: %d task files\n", len(resources))
+
+		return nil
+	},
+}
+
+// newMCPServer creates an MCP server over the project store
+func newMCPServer() *mcp.Server {
+	return mcp.NewServer(store, newService(resolveAgent(mcpAgent)), "")
+}
+
+func init() {
+	mcpCmd.AddCommand(mcpCheckCmd)
+	mcpCmd.PersistentFlags().StringVar(&mcpAgent, "agent", "", "Agent name (default $STRAND_AGENT or $USER)")
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/git"
+	"github.com/hamsa0x7/strand/internal/history"
+	"github.com/hamsa0x7/strand/internal/markdown"
+	"github.com/hamsa0x7/strand/internal/merge"
+	"github.com/spf13/cobra"
+)
+
+// mergeDriver is the git config value that runs the driver
+const mergeDriver = "strand merge-driver %O %A %B"
+
+// mergeAttributes go in .strand/.gitattributes: task files use the driver,
+// and the append-only history keeps the lines of both sides
+var mergeAttributes = []string{
+	"tasks/*.md merge=strand",
+	history.FileName + " merge=union",
+}
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <current> <other>",
+	Short: "Merge two versions of a task file (git merge driver)",
+	Long: `Three-way merge of a task file for git, writing the result over <current>.
+
+Fields changed on one side take that change; fields changed on both take
+the side updated last. Tags and dependencies keep the additions and removals
+of both sides, comments and time entries are combined, and the description
+is merged as text. Only description conflicts are left for you to resolve,
+marked in the description so the file stays readable by strand.
+
+Register it with 'strand init --git', which sets in the git config:
+
+  merge.strand.driver = ` + mergeDriver,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		basePath, currentPath, otherPath := args[0], args[1], args[2]
+
+		base, err := readMergeVersion(basePath, true)
+		if err != nil {
+			return textMerge(currentPath, basePath, otherPath, err)
+		}
+		current, err := readMergeVersion(currentPath, false)
+		if err != nil {
+			return textMerge(currentPath, basePath, otherPath, err)
+		}
+		other, err := readMergeVersion(otherPath, false)
+		if err != nil {
+			return textMerge(currentPath, basePath, otherPath, err)
+		}
+
+		merged, conflicts, err := merge.Tasks(base, current, other, git.MergeText)
+		if err != nil {
+			return err
+		}
+		content, err := markdown.Format(merged)
+		if err != nil {
+			return fmt.Errorf("failed to format merged task: %w", err)
+		}
+		if err := os.WriteFile(currentPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write merged task: %w", err)
+		}
+
+		if conflicts {
+			return fmt.Errorf("%s: both sides changed the description; resolve the conflict markers in it", merged.ID)
+		}
+		return nil
+	},
+}
+
+// readMergeVersion parses one version of a task file. An empty base means
+// both sides added the file and is returned as nil.
+func readMergeVersion(path string, isBase bool) (*core.Task, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isBase && len(data) == 0 {
+		return nil, nil
+	}
+	return markdown.Parse(data, path)
+}
+
+// textMerge falls back to git's line-based merge for a version strand
+// cannot parse
+func textMerge(currentPath, basePath, otherPath string, parseErr error) error {
+	fmt.Fprintf(os.Stderr, "strand: %v; merging as text\n", parseErr)
+
+	conflicts, err := git.MergeFile(currentPath, basePath, otherPath)
+	if err != nil {
+		return err
+	}
+	if conflicts {
+		return fmt.Errorf("conflicts left in %s", currentPath)
+	}
+	return nil
+}
+
+// registerMergeDriver sets up the merge driver for the project in
+// strandDir: task files get the strand merge attribute and the repository
+// config says how to run it
+func registerMergeDriver(strandDir string) error {
+	repo, err := git.Open(strandDir)
+	if err != nil {
+		return fmt.Errorf("--git needs a git repository (run 'git init' first): %w", err)
+	}
+
+	path := filepath.Join(strandDir, ".gitattributes")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	content := string(data)
+	for _, line := range mergeAttributes {
+		if strings.Contains(content, line) {
+			continue
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += line + "\n"
+	}
+	if content != string(data) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	if err := repo.SetConfig("merge.strand.name", "strand task merge"); err != nil {
+		return err
+	}
+	return repo.SetConfig("merge.strand.driver", mergeDriver)
+}
@@ -4,22 +4,61 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/graph"
 	"github.com/spf13/cobra"
 )
 
+var (
+	readyWhere    string
+	readyStarted  bool
+	readySort     string
+	readyLimit    int
+	readyAssignee string
+	readyTags     []string
+	readyType     string
+)
+
+// readySortKeys are the --sort values ready accepts besides the list keys
+var readySortKeys = []string{"score", "unblocks"}
+
+// rankedTask is a ready task with its score
+type rankedTask struct {
+	*core.Task
+	Score   float64           `json:"score"`
+	Factors core.ScoreFactors `json:"factors"`
+	impact  graph.Impact
+}
+
 var readyCmd = &cobra.Command{
 	Use:   "ready",
 	Short: "List tasks ready to work on",
-	Long:  `List tasks that have no blocking dependencies and are ready to be worked on.`,
+	Long: `List tasks that have no blocking dependencies and are ready to be worked on,
+best first.
+
+Tasks are ranked by a score combining priority, due date, age, how many open
+tasks wait on them and whether they are already assigned. The weights live
+under ready.weights in .strand/config.yaml.
+
+Use --started to leave out tasks whose start date is still in the future.
+--sort unblocks puts the tasks that free up the most other work first.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		tasks, err := store.Ready()
+		ranked, err := rankReady()
 		if err != nil {
-			return fmt.Errorf("failed to get ready tasks: %w", err)
+			return err
 		}
 
-		if len(tasks) == 0 {
+		if len(ranked) == 0 {
+			if outputJSON {
+				fmt.Println("[]")
+				return nil
+			}
 			fmt.Println("No ready tasks found.")
 			fmt.Println("All tasks either:")
 			fmt.Println("  - Are already done/cancelled")
@@ -28,33 +67,226 @@ var readyCmd = &cobra.Command{
 		}
 
 		if outputJSON {
-			data, _ := json.MarshalIndent(tasks, "", "  ")
+			data, _ := json.MarshalIndent(ranked, "", "  ")
 			fmt.Println(string(data))
 			return nil
 		}
 
 		// Table output
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tPRIORITY\tTITLE")
-		fmt.Fprintln(w, "──\t────\t──────\t────────\t─────")
+		fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tPRIORITY\tSCORE\tUNBLOCKS\tTITLE")
+		fmt.Fprintln(w, "──\t────\t──────\t────────\t─────\t────────\t─────")
 
-		for _, task := range tasks {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		for _, task := range ranked {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.1f\t%s\t%s\n",
 				task.ID,
 				task.Type,
 				task.Status,
 				task.Priority,
+				task.Score,
+				formatImpact(task.impact),
 				task.Title,
 			)
 		}
 
 		w.Flush()
-		fmt.Printf("\n✅ %d tasks ready to work on\n", len(tasks))
+		fmt.Printf("\n✅ %d tasks ready to work on\n", len(ranked))
+
+		return nil
+	},
+}
+
+var nextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Show the single best task to work on",
+	Long: `Print the top task of 'strand ready', with the same filters and ranking.
+
+With --json, prints the task object, or null if nothing is ready.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		readyLimit = 1
+		ranked, err := rankReady()
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			if len(ranked) == 0 {
+				fmt.Println("null")
+				return nil
+			}
+			data, _ := json.MarshalIndent(ranked[0], "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(ranked) == 0 {
+			fmt.Println("No ready tasks found.")
+			return nil
+		}
+
+		task := ranked[0]
+		fmt.Printf("%s - %s\n", task.ID, task.Title)
+		fmt.Printf("   Type: %s\n", task.Type)
+		fmt.Printf("   Priority: %s\n", task.Priority)
+		if task.Due != nil {
+			fmt.Printf("   Due: %s\n", formatDue(task.Task, time.Now()))
+		}
+		if task.Assignee != "" {
+			fmt.Printf("   Assignee: %s\n", task.Assignee)
+		}
+		if task.impact.Blocks > 0 {
+			fmt.Printf("   Unblocks: %s\n", formatImpact(task.impact))
+		}
+		fmt.Printf("   Score: %.1f\n", task.Score)
 
 		return nil
 	},
 }
 
+// rankReady returns the ready tasks that pass the ready flags, scored and
+// sorted, up to --limit
+func rankReady() ([]*rankedTask, error) {
+	if readySort != "" && !contains(readySortKeys, readySort) && validateSortKey(readySort) != nil {
+		return nil, fmt.Errorf("invalid sort '%s'. Valid: %s, %s", readySort, strings.Join(readySortKeys, ", "), strings.Join(sortKeys, ", "))
+	}
+	if readyType != "" {
+		if err := ValidateType(readyType); err != nil {
+			return nil, err
+		}
+	}
+
+	tasks, err := store.Ready()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ready tasks: %w", err)
+	}
+
+	tasks, err = filterWhere(tasks, readyWhere)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var filtered []*core.Task
+	for _, task := range tasks {
+		switch {
+		case readyStarted && !task.HasStarted(now):
+		case readyType != "" && string(task.Type) != readyType:
+		case readyAssignee == "none" && task.Assignee != "":
+		case readyAssignee != "" && readyAssignee != "none" && task.Assignee != readyAssignee:
+		case len(readyTags) > 0 && !hasAnyTag(task, readyTags):
+		default:
+			filtered = append(filtered, task)
+		}
+	}
+
+	allTasks, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	taskMap := graph.TaskMap(allTasks)
+	weights := projectCfg.ScoreWeights()
+
+	ranked := make([]*rankedTask, 0, len(filtered))
+	for _, task := range filtered {
+		impact := graph.ComputeImpact(taskMap, task.ID)
+		factors := task.Factors(now, impact.Blocks)
+		ranked = append(ranked, &rankedTask{
+			Task:    task,
+			Score:   weights.Score(factors),
+			Factors: factors,
+			impact:  impact,
+		})
+	}
+
+	switch readySort {
+	case "", "score":
+		sortByScore(ranked)
+	case "unblocks":
+		sortByImpact(ranked)
+	default:
+		tasks := make([]*core.Task, len(ranked))
+		byID := make(map[string]*rankedTask, len(ranked))
+		for i, r := range ranked {
+			tasks[i] = r.Task
+			byID[r.ID] = r
+		}
+		sortTasks(tasks, readySort)
+		for i, task := range tasks {
+			ranked[i] = byID[task.ID]
+		}
+	}
+
+	if readyLimit > 0 && len(ranked) > readyLimit {
+		ranked = ranked[:readyLimit]
+	}
+
+	return ranked, nil
+}
+
+// sortByScore puts the highest score first, then the oldest task
+func sortByScore(ranked []*rankedTask) {
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// sortByImpact puts the tasks that directly unblock the most work first,
+// then those with the most work waiting on them, then by priority
+func sortByImpact(ranked []*rankedTask) {
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i].impact, ranked[j].impact
+		if len(a.Unblocks) != len(b.Unblocks) {
+			return len(a.Unblocks) > len(b.Unblocks)
+		}
+		if a.Blocks != b.Blocks {
+			return a.Blocks > b.Blocks
+		}
+		if ra, rb := core.PriorityRank(ranked[i].Priority), core.PriorityRank(ranked[j].Priority); ra != rb {
+			return ra < rb
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+}
+
+// formatImpact renders the UNBLOCKS column, e.g. "2 (5)" when completing a
+// task unblocks two tasks and five wait on it in total
+func formatImpact(impact graph.Impact) string {
+	if impact.Blocks == 0 {
+		return ""
+	}
+	if impact.Blocks == len(impact.Unblocks) {
+		return fmt.Sprintf("%d", impact.Blocks)
+	}
+	return fmt.Sprintf("%d (%d)", len(impact.Unblocks), impact.Blocks)
+}
+
+// hasAnyTag reports whether task has at least one of tags
+func hasAnyTag(task *core.Task, tags []string) bool {
+	for _, tag := range task.Tags {
+		if contains(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
-	readyCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	for _, cmd := range []*cobra.Command{readyCmd, nextCmd} {
+		cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+		cmd.Flags().StringVarP(&readyWhere, "where", "w", "", whereHelp)
+		cmd.Flags().BoolVar(&readyStarted, "started", false, "Exclude tasks whose start date is in the future")
+		cmd.Flags().StringVar(&readySort, "sort", "score", "Sort by score, unblocks, or a list sort key ("+strings.Join(sortKeys, ", ")+")")
+		cmd.Flags().StringVarP(&readyAssignee, "assignee", "a", "", "Only tasks assigned to this agent (none for unassigned)")
+		cmd.Flags().StringSliceVar(&readyTags, "tag", nil, "Only tasks with any of these tags (comma-separated)")
+		cmd.Flags().StringVarP(&readyType, "type", "t", "", "Only tasks of this type (task|epic|bug|story)")
+	}
+	readyCmd.Flags().IntVarP(&readyLimit, "limit", "n", 0, "Show at most N tasks (0 = all)")
 }
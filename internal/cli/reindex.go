@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/hamsa0x7/strand/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the SQLite cache",
+	Long: `Drop the SQLite cache in .strand/.cache and rebuild it from the markdown files.
+
+The cache normally updates itself by re-reading only files whose size or
+modification time changed. Use this if it ever gets out of sync.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cached, ok := store.(*cache.Store)
+		if !ok {
+			return fmt.Errorf("cache is not available")
+		}
+
+		count, err := cached.Reindex()
+		if err != nil {
+			return fmt.Errorf("failed to reindex: %w", err)
+		}
+
+		fmt.Printf("✅ Reindexed %d tasks\n", count)
+
+		return nil
+	},
+}
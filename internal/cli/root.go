@@ -4,25 +4,40 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/hamsa0x7/strand/internal/cache"
+	"github.com/hamsa0x7/strand/internal/config"
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/lock"
 	"github.com/hamsa0x7/strand/internal/markdown"
 	"github.com/hamsa0x7/strand/internal/storage"
 	"github.com/spf13/cobra"
 )
 
 var (
-	store     storage.Store
-	strandDir string
+	store       storage.Store
+	strandDir   string
+	projectCfg  *config.Config
+	projectLock *lock.Lock
 )
 
+// lockTimeout bounds how long a mutating command waits for another one
+const lockTimeout = 10 * time.Second
+
+// mutating annotates commands that modify tasks; they run while holding an
+// exclusive lock on .strand/ so concurrent read-modify-write cycles serialize
+var mutating = map[string]string{"strand.mutating": "true"}
+
 var rootCmd = &cobra.Command{
 	Use:   "strand",
 	Short: "Strand - Task tracking for humans and AI agents",
 	Long: `Strand is a git-backed, dependency-aware task tracking system 
 that stores tasks as human-readable Markdown with embedded metadata.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		// Initialize storage for all commands except init
-		if cmd.Name() == "init" {
+		// Initialize storage for all commands except init and the merge
+		// driver, which git runs on temporary files
+		if cmd.Name() == "init" || cmd.Name() == "merge-driver" {
 			return nil
 		}
 
@@ -33,10 +48,35 @@ that stores tasks as human-readable Markdown with embedded metadata.`,
 		}
 		strandDir = dir
 
-		// Initialize store
-		store = markdown.NewStore()
+		// Load the project workflow before anything reads statuses
+		projectCfg, err = config.Load(strandDir)
+		if err != nil {
+			return err
+		}
+		workflow, err := projectCfg.BuildWorkflow()
+		if err != nil {
+			return err
+		}
+		core.SetWorkflow(workflow)
+
+		// Initialize store, serving reads from the SQLite cache when it
+		// is available and falling back to plain markdown otherwise
+		store = cache.NewStore()
 		if err := store.Init(strandDir); err != nil {
-			return fmt.Errorf("failed to initialize storage: %w", err)
+			fmt.Fprintf(os.Stderr, "warning: cache unavailable, reading markdown directly: %v\n", err)
+
+			store = markdown.NewStore()
+			if err := store.Init(strandDir); err != nil {
+				return fmt.Errorf("failed to initialize storage: %w", err)
+			}
+		}
+
+		// Serialize mutating commands across processes
+		if cmd.Annotations["strand.mutating"] == "true" {
+			projectLock, err = lock.Acquire(filepath.Join(strandDir, ".lock"), lockTimeout)
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -45,7 +85,17 @@ that stores tasks as human-readable Markdown with embedded metadata.`,
 
 // Execute runs the root command
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	// Only mutating commands hold the lock; the others commit their own
+	// writes while they hold it
+	if projectLock != nil {
+		autoCommit() // Even if some changes failed
+		projectLock.Release()
+	}
+	if store != nil {
+		store.Close()
+	}
+	return err
 }
 
 func init() {
@@ -55,12 +105,36 @@ func init() {
 	rootCmd.AddCommand(showCmd)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(readyCmd)
+	rootCmd.AddCommand(nextCmd)
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(depCmd)
 	rootCmd.AddCommand(editCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(graphCmd)
 	rootCmd.AddCommand(tuiCmd)
+	rootCmd.AddCommand(claimCmd)
+	rootCmd.AddCommand(heartbeatCmd)
+	rootCmd.AddCommand(releaseCmd)
+	rootCmd.AddCommand(reindexCmd)
+	rootCmd.AddCommand(childrenCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(logCmd)
+	rootCmd.AddCommand(commentCmd)
+	rootCmd.AddCommand(commentsCmd)
+	rootCmd.AddCommand(overdueCmd)
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(logTimeCmd)
+	rootCmd.AddCommand(timeCmd)
+	rootCmd.AddCommand(criticalPathCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(tagsCmd)
+	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(gitCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(mergeDriverCmd)
 }
 
 // findStrandDir searches for .strand directory in current or parent directories
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/spf13/cobra"
+)
+
+// dateHelp describes the values accepted by --due and --start
+const dateHelp = "today, tomorrow, friday, +3d, +2w or 2026-11-01"
+
+var overdueWithin int
+
+var overdueCmd = &cobra.Command{
+	Use:   "overdue",
+	Short: "List open tasks past their due date",
+	Long: `List open tasks whose due date has passed, most overdue first.
+
+Use --within to also include tasks due in the next few days.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+
+		now := time.Now()
+		var due []*core.Task
+		for _, task := range tasks {
+			if task.IsOverdue(now) || (overdueWithin > 0 && task.IsDueWithin(now, overdueWithin)) {
+				due = append(due, task)
+			}
+		}
+		sortTasks(due, "due")
+
+		if outputJSON {
+			if due == nil {
+				due = []*core.Task{}
+			}
+			data, _ := json.MarshalIndent(due, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(due) == 0 {
+			fmt.Println("No overdue tasks.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tDUE\tWHEN\tSTATUS\tASSIGNEE\tTITLE")
+		fmt.Fprintln(w, "──\t───\t────\t──────\t────────\t─────")
+
+		for _, task := range due {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				task.ID,
+				dates.Format(*task.Due),
+				describeDue(task, now),
+				task.Status,
+				task.Assignee,
+				task.Title,
+			)
+		}
+
+		w.Flush()
+		fmt.Printf("\nTotal: %d tasks\n", len(due))
+
+		return nil
+	},
+}
+
+// describeDue says how far away a task's due date is, e.g. "3d overdue"
+func describeDue(task *core.Task, now time.Time) string {
+	if task.Due == nil {
+		return ""
+	}
+
+	days := dates.DaysBetween(now, *task.Due)
+	switch {
+	case core.IsClosed(task.Status):
+		return ""
+	case days < 0:
+		return fmt.Sprintf("%dd overdue", -days)
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	default:
+		return fmt.Sprintf("in %dd", days)
+	}
+}
+
+// formatDue renders the list DUE column
+func formatDue(task *core.Task, now time.Time) string {
+	if task.Due == nil {
+		return ""
+	}
+	if task.IsOverdue(now) {
+		return dates.Format(*task.Due) + " (overdue)"
+	}
+	return dates.Format(*task.Due)
+}
+
+// tableRule underlines a tabwriter header
+func tableRule(header []string) string {
+	rule := make([]string, len(header))
+	for i, h := range header {
+		rule[i] = strings.Repeat("─", len([]rune(h)))
+	}
+	return strings.Join(rule, "\t")
+}
+
+// sortKeys lists the values accepted by --sort
+var sortKeys = []string{"id", "priority", "due", "start", "created", "updated", "status", "title"}
+
+// validateSortKey checks a --sort value
+func validateSortKey(key string) error {
+	for _, k := range sortKeys {
+		if key == k {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid sort '%s'. Valid: %s", key, strings.Join(sortKeys, ", "))
+}
+
+// sortTasks orders tasks by key, falling back to ID. Tasks without a due
+// or start date sort last.
+func sortTasks(tasks []*core.Task, key string) {
+	optional := func(a, b *time.Time) (less, decided bool) {
+		switch {
+		case a == nil && b == nil:
+			return false, false
+		case a == nil:
+			return false, true
+		case b == nil:
+			return true, true
+		case !a.Equal(*b):
+			return a.Before(*b), true
+		}
+		return false, false
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		switch key {
+		case "priority":
+			if ra, rb := core.PriorityRank(a.Priority), core.PriorityRank(b.Priority); ra != rb {
+				return ra < rb
+			}
+		case "due":
+			if less, ok := optional(a.Due, b.Due); ok {
+				return less
+			}
+		case "start":
+			if less, ok := optional(a.Start, b.Start); ok {
+				return less
+			}
+		case "created":
+			if !a.Created.Equal(b.Created) {
+				return a.Created.Before(b.Created)
+			}
+		case "updated":
+			// Most recently updated first
+			if !a.Updated.Equal(b.Updated) {
+				return a.Updated.After(b.Updated)
+			}
+		case "status":
+			if a.Status != b.Status {
+				return statusIndex(a.Status) < statusIndex(b.Status)
+			}
+		case "title":
+			if ta, tb := strings.ToLower(a.Title), strings.ToLower(b.Title); ta != tb {
+				return ta < tb
+			}
+		}
+		return a.ID < b.ID
+	})
+}
+
+// statusIndex orders statuses as the workflow lists them
+func statusIndex(status core.TaskStatus) int {
+	for i, s := range core.CurrentWorkflow().Statuses {
+		if s == status {
+			return i
+		}
+	}
+	return len(core.CurrentWorkflow().Statuses)
+}
+
+func init() {
+	overdueCmd.Flags().IntVar(&overdueWithin, "within", 0, "Also include tasks due in the next N days")
+	overdueCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+}
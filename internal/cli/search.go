@@ -7,7 +7,9 @@ import (
 	"strings"
 	"text/tabwriter"
 
+	"github.com/hamsa0x7/strand/internal/cache"
 	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -15,6 +17,7 @@ var (
 	searchStatus   string
 	searchPriority string
 	searchTags     []string
+	searchWhere    string
 )
 
 var searchCmd = &cobra.Command{
@@ -22,27 +25,63 @@ var searchCmd = &cobra.Command{
 	Short: "Search for tasks",
 	Long: `Search for tasks by title, description, or metadata.
 	
-The query is matched against task titles and descriptions (case-insensitive).
-Use flags to filter by status, priority, or tags.`,
-	Args: cobra.MinimumNArgs(1),
+The query is matched against task titles, descriptions and tags. When the
+SQLite cache has FTS5, results are ranked by BM25 with stemming, so "login"
+also finds "logins"; use "exact phrase" for phrases and auth* for prefixes.
+Without FTS5 the query is a case-insensitive substring match.
+Use flags to filter by status, priority, or tags, or --where for a full
+filter expression (see 'strand list --help'). The query may be omitted
+when --where is given.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && searchWhere == "" {
+			return fmt.Errorf("requires a query or --where expression")
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		query := strings.Join(args, " ")
 		queryLower := strings.ToLower(query)
 
-		// Get all tasks
-		allTasks, err := store.List()
+		where, err := parseWhere(searchWhere)
+		if err != nil {
+			return err
+		}
+		env := queryEnv()
+
+		// Get candidate tasks, letting the store search natively if it can
+		var hits []*storage.SearchHit
+		searcher, native := store.(storage.Searcher)
+		if native && query != "" {
+			hits, err = searcher.Search(query)
+		} else {
+			native = false
+			var allTasks []*core.Task
+			allTasks, err = store.List()
+			for _, task := range allTasks {
+				hits = append(hits, &storage.SearchHit{Task: task})
+			}
+		}
 		if err != nil {
-			return fmt.Errorf("failed to list tasks: %w", err)
+			return fmt.Errorf("failed to search tasks: %w", err)
 		}
 
 		// Filter tasks
-		var matches []*core.Task
-		for _, task := range allTasks {
+		var matches []*storage.SearchHit
+		for _, hit := range hits {
+			task := hit.Task
+
 			// Text search
-			titleMatch := strings.Contains(strings.ToLower(task.Title), queryLower)
-			descMatch := strings.Contains(strings.ToLower(task.Description), queryLower)
+			if !native {
+				titleMatch := strings.Contains(strings.ToLower(task.Title), queryLower)
+				descMatch := strings.Contains(strings.ToLower(task.Description), queryLower)
+
+				if !titleMatch && !descMatch {
+					continue
+				}
+			}
 
-			if !titleMatch && !descMatch {
+			// Expression filter
+			if where != nil && !where.Match(task, env) {
 				continue
 			}
 
@@ -77,27 +116,65 @@ Use flags to filter by status, priority, or tags.`,
 				}
 			}
 
-			matches = append(matches, task)
+			matches = append(matches, hit)
 		}
 
 		// Output results
+		if query == "" {
+			query = searchWhere
+		}
 		if len(matches) == 0 {
+			if outputJSON {
+				fmt.Println("[]")
+				return nil
+			}
 			fmt.Printf("No tasks found matching '%s'\n", query)
 			return nil
 		}
 
 		if outputJSON {
+			for _, hit := range matches {
+				hit.Snippet = highlightSnippet(hit.Snippet, "**", "**")
+			}
 			data, _ := json.MarshalIndent(matches, "", "  ")
 			fmt.Println(string(data))
 			return nil
 		}
 
+		// Show snippets only when the store produced them
+		withSnippets := false
+		for _, hit := range matches {
+			if hit.Snippet != "" {
+				withSnippets = true
+				break
+			}
+		}
+
 		// Table output
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tPRIORITY\tTITLE")
-		fmt.Fprintln(w, "──\t────\t──────\t────────\t─────")
+		if withSnippets {
+			fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tPRIORITY\tSCORE\tTITLE\tMATCH")
+			fmt.Fprintln(w, "──\t────\t──────\t────────\t─────\t─────\t─────")
+		} else {
+			fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tPRIORITY\tTITLE")
+			fmt.Fprintln(w, "──\t────\t──────\t────────\t─────")
+		}
 
-		for _, task := range matches {
+		on, off := highlightCodes()
+		for _, hit := range matches {
+			task := hit.Task
+			if withSnippets {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.3g\t%s\t%s\n",
+					task.ID,
+					task.Type,
+					task.Status,
+					task.Priority,
+					hit.Score,
+					task.Title,
+					highlightSnippet(hit.Snippet, on, off),
+				)
+				continue
+			}
 			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 				task.ID,
 				task.Type,
@@ -114,9 +191,31 @@ Use flags to filter by status, priority, or tags.`,
 	},
 }
 
+// highlightSnippet replaces the cache's match markers with on/off and
+// flattens the snippet onto one line
+func highlightSnippet(snippet, on, off string) string {
+	snippet = strings.NewReplacer(
+		cache.HighlightStart, on,
+		cache.HighlightEnd, off,
+		"\r\n", " ",
+		"\n", " ",
+	).Replace(snippet)
+	return strings.TrimSpace(snippet)
+}
+
+// highlightCodes returns ANSI bold-yellow codes for terminals, or plain
+// brackets when output is redirected
+func highlightCodes() (string, string) {
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		return "\x1b[1;33m", "\x1b[0m"
+	}
+	return "[", "]"
+}
+
 func init() {
 	searchCmd.Flags().StringVar(&searchStatus, "status", "", "Filter by status")
 	searchCmd.Flags().StringVar(&searchPriority, "priority", "", "Filter by priority")
 	searchCmd.Flags().StringSliceVar(&searchTags, "tags", []string{}, "Filter by tags (comma-separated)")
 	searchCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	searchCmd.Flags().StringVarP(&searchWhere, "where", "w", "", whereHelp)
 }
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/api"
+	"github.com/hamsa0x7/strand/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr  string
+	serveToken string
+	serveAgent string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve tasks over a local HTTP/JSON API",
+	Long: `Run an HTTP server exposing tasks as JSON, for editor plugins, dashboards
+and scripts.
+
+  GET    /api/tasks                        List tasks (?where=, status, assignee, tag, limit)
+  POST   /api/tasks                        Create a task
+  GET    /api/tasks/{id}                   Get a task
+  PATCH  /api/tasks/{id}                   Change a task's fields
+  DELETE /api/tasks/{id}                   Delete a task
+  GET    /api/tasks/{id}/dependencies      Tasks it depends on
+  POST   /api/tasks/{id}/dependencies      Add a dependency: {"depends_on": "<id>"}
+  DELETE /api/tasks/{id}/dependencies/{dep}
+  GET    /api/tasks/{id}/dependents        Tasks waiting on it (?transitive=true)
+  GET    /api/ready                        Ready queue, best first
+  POST   /api/claim                        Claim the best ready task
+  GET    /api/search?q=                    Full-text search
+  GET    /api/events                       Server-Sent Events stream of task changes
+
+Task responses carry an ETag. Send it back in If-Match on PATCH, DELETE or
+dependency changes, and the server answers 412 if the task changed since.
+
+Set --token (or $STRAND_TOKEN) to require "Authorization: Bearer <token>".
+Each write takes the project lock, so the server can run alongside the CLI.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := serveToken
+		if token == "" {
+			token = os.Getenv("STRAND_TOKEN")
+		}
+
+		watcher, err := newWatcher(0)
+		if err != nil {
+			return err
+		}
+
+		listener, err := net.Listen("tcp", serveAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", serveAddr, err)
+		}
+		if token == "" && !isLoopback(listener.Addr()) {
+			fmt.Fprintf(os.Stderr, "⚠️  Serving on %s without a token; anyone who can reach it can change tasks\n", listener.Addr())
+		}
+
+		events := api.NewBroker()
+		server := &http.Server{
+			Handler:           api.NewServer(newService(resolveAgent(serveAgent)), events, token),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// Stream every change to the task files, including ones made
+		// outside the server
+		go func() {
+			if err := watcher.Run(ctx, func(batch []watch.Event) { events.Publish(batch...) }); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: event stream stopped: %v\n", err)
+			}
+		}()
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdown)
+		}()
+
+		fmt.Printf("Serving strand API on http://%s (Ctrl+C to stop)\n", listener.Addr())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	},
+}
+
+// isLoopback reports whether addr only accepts local connections
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:7777", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Require this bearer token (default $STRAND_TOKEN)")
+	serveCmd.Flags().StringVar(&serveAgent, "agent", "", "Agent recorded in the history (default $STRAND_AGENT, then $USER)")
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"path/filepath"
+
+	"github.com/hamsa0x7/strand/internal/lock"
+	"github.com/hamsa0x7/strand/internal/service"
+)
+
+// newService creates the task service long-running commands share. Each
+// write takes the project lock, so they can run alongside the CLI.
+func newService(agent string) *service.Service {
+	return service.New(store, service.Options{
+		Agent:   agent,
+		Weights: projectCfg.ScoreWeights(),
+		Lock:    lockProject,
+		Record:  recordEvents,
+		Commit:  autoCommit, // Each write with its follow-ups, still holding the lock
+	})
+}
+
+// cliService creates the task service for a mutating command. The command
+// already holds the project lock and commits its changes when it ends, so
+// the service does neither.
+func cliService(agent string) *service.Service {
+	return service.New(store, service.Options{
+		Agent:   agent,
+		Weights: projectCfg.ScoreWeights(),
+		Record:  recordEvents,
+	})
+}
+
+// lockProject takes the project lock for a write made outside a mutating
+// command and returns the function that releases it
+func lockProject() (func(), error) {
+	l, err := lock.Acquire(filepath.Join(strandDir, ".lock"), lockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return func() { l.Release() }, nil
+}
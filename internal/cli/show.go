@@ -3,7 +3,12 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/hamsa0x7/strand/internal/graph"
 	"github.com/spf13/cobra"
 )
 
@@ -20,8 +25,28 @@ var showCmd = &cobra.Command{
 			return err
 		}
 
+		// Roll up progress for epics and other tasks with children
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+		taskMap := graph.TaskMap(tasks)
+		rollup := graph.ComputeRollup(taskMap, task.ID)
+
+		var blocks []string
+		for _, dep := range graph.DirectDependents(taskMap, task.ID) {
+			blocks = append(blocks, dep.ID)
+		}
+		impact := graph.ComputeImpact(taskMap, task.ID)
+
 		if outputJSON {
-			data, _ := json.MarshalIndent(task, "", "  ")
+			result := struct {
+				taskView
+				Rollup *graph.Rollup `json:"rollup,omitempty"`
+				Blocks []string      `json:"blocks,omitempty"`
+				Impact graph.Impact  `json:"impact"`
+			}{viewTasks([]*core.Task{task}, taskMap)[0], rollup, blocks, impact}
+			data, _ := json.MarshalIndent(result, "", "  ")
 			fmt.Println(string(data))
 			return nil
 		}
@@ -29,14 +54,54 @@ var showCmd = &cobra.Command{
 		// Pretty output
 		fmt.Printf("ID:          %s\n", task.ID)
 		fmt.Printf("Type:        %s\n", task.Type)
-		fmt.Printf("Status:      %s\n", task.Status)
+		if open := task.OpenDependencies(taskMap); len(open) > 0 && !core.IsClosed(task.Status) {
+			fmt.Printf("Status:      %s (%s, waiting on %s)\n", core.TaskStatusBlocked, task.Status, strings.Join(open, ", "))
+		} else {
+			fmt.Printf("Status:      %s\n", task.Status)
+		}
 		fmt.Printf("Priority:    %s\n", task.Priority)
 		fmt.Printf("Title:       %s\n", task.Title)
 
+		if task.Parent != "" {
+			fmt.Printf("Parent:      %s\n", task.Parent)
+		}
+
+		if task.AutoClose {
+			fmt.Printf("Auto-close:  yes\n")
+		}
+
+		if task.Due != nil {
+			fmt.Printf("Due:         %s", dates.Format(*task.Due))
+			if when := describeDue(task, time.Now()); when != "" {
+				fmt.Printf(" (%s)", when)
+			}
+			fmt.Println()
+		}
+
+		if task.Start != nil {
+			fmt.Printf("Start:       %s\n", dates.Format(*task.Start))
+		}
+
+		if task.Estimate > 0 {
+			fmt.Printf("Estimate:    %s\n", dates.FormatDuration(time.Duration(task.Estimate)))
+		}
+
+		if len(task.TimeLog) > 0 {
+			fmt.Printf("Logged:      %s", formatEffort(task))
+			if running := task.RunningTimers(); len(running) > 0 {
+				fmt.Printf(" (timer running: %s)", strings.Join(running, ", "))
+			}
+			fmt.Println()
+		}
+
 		if task.Assignee != "" {
 			fmt.Printf("Assignee:    %s\n", task.Assignee)
 		}
 
+		if task.LeaseExpires != nil {
+			fmt.Printf("Lease:       expires %s\n", task.LeaseExpires.Format("2006-01-02 15:04:05"))
+		}
+
 		if len(task.Tags) > 0 {
 			fmt.Printf("Tags:        %v\n", task.Tags)
 		}
@@ -45,6 +110,16 @@ var showCmd = &cobra.Command{
 			fmt.Printf("Depends On:  %v\n", task.DependsOn)
 		}
 
+		if len(blocks) > 0 {
+			fmt.Printf("Blocks:      %v\n", blocks)
+			if impact.Blocks > 0 {
+				fmt.Printf("Impact:      completing this unblocks %d task(s), %d wait on it in total\n",
+					len(impact.Unblocks), impact.Blocks)
+			}
+		}
+
+		printRollup(rollup)
+
 		fmt.Printf("Created:     %s\n", task.Created.Format("2006-01-02 15:04:05"))
 		fmt.Printf("Updated:     %s\n", task.Updated.Format("2006-01-02 15:04:05"))
 		fmt.Printf("File:        %s\n", task.FilePath)
@@ -54,6 +129,15 @@ var showCmd = &cobra.Command{
 			fmt.Println(task.Description)
 		}
 
+		if len(task.Comments) > 0 {
+			fmt.Printf("\nComments (%d):\n", len(task.Comments))
+			printComments(task.Comments)
+		}
+
 		return nil
 	},
 }
+
+func init() {
+	showCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/git"
+	"github.com/hamsa0x7/strand/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+// statusReport is the JSON output of strand status
+type statusReport struct {
+	Tasks       int                     `json:"tasks"`
+	ByStatus    map[core.TaskStatus]int `json:"by_status"`
+	Git         bool                    `json:"git"`
+	AutoCommit  bool                    `json:"auto_commit"`
+	Uncommitted []uncommittedTask       `json:"uncommitted"`
+}
+
+// uncommittedTask is a task file with changes git has not committed
+type uncommittedTask struct {
+	ID     string `json:"id"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status"` // added, modified, deleted, renamed or untracked
+	Path   string `json:"path"`
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Summarize the project and its uncommitted task changes",
+	Long: `Count tasks by status (with tasks waiting on open dependencies counted
+as blocked) and, in a git repository, list task files whose changes are not
+committed yet.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+		taskMap := graph.TaskMap(tasks)
+
+		report := statusReport{
+			Tasks:       len(tasks),
+			ByStatus:    make(map[core.TaskStatus]int),
+			AutoCommit:  projectCfg.Git.AutoCommit,
+			Uncommitted: []uncommittedTask{},
+		}
+		for _, task := range tasks {
+			report.ByStatus[task.EffectiveStatus(taskMap)]++
+		}
+
+		if repo, err := git.Open(strandDir); err == nil {
+			report.Git = true
+			if report.Uncommitted, err = uncommittedTasks(repo, taskMap); err != nil {
+				return err
+			}
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		return printStatus(report)
+	},
+}
+
+// uncommittedTasks lists the task files with uncommitted changes
+func uncommittedTasks(repo *git.Repo, taskMap map[string]*core.Task) ([]uncommittedTask, error) {
+	tasksDir, err := repo.Rel(filepath.Join(strandDir, "tasks"))
+	if err != nil {
+		return nil, err
+	}
+	files, err := repo.Status(tasksDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []uncommittedTask{}
+	for _, f := range files {
+		id := strings.TrimSuffix(filepath.Base(f.Path), ".md")
+		u := uncommittedTask{ID: id, Status: f.Status, Path: f.Path}
+		if task, ok := taskMap[id]; ok {
+			u.Title = task.Title
+		}
+		result = append(result, u)
+	}
+	return result, nil
+}
+
+// printStatus prints the report for people
+func printStatus(report statusReport) error {
+	fmt.Printf("Tasks: %d\n", report.Tasks)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	workflow := core.CurrentWorkflow()
+	statuses := append([]core.TaskStatus{}, workflow.Statuses...)
+	// Derived statuses such as blocked, and any a config change left
+	// behind, follow the workflow's own so the rows add up to the total
+	var extra []core.TaskStatus
+	for status := range report.ByStatus {
+		if !workflow.IsValid(status) {
+			extra = append(extra, status)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+	for _, status := range append(statuses, extra...) {
+		if n := report.ByStatus[status]; n > 0 {
+			fmt.Fprintf(w, "  %s\t%d\n", status, n)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	if !report.Git {
+		fmt.Println("Not in a git repository (or git is not installed).")
+		return nil
+	}
+	if report.AutoCommit {
+		fmt.Println("Git: auto-commit on")
+	} else {
+		fmt.Println("Git: auto-commit off (set git.auto_commit in .strand/config.yaml)")
+	}
+
+	if len(report.Uncommitted) == 0 {
+		fmt.Println("✅ All task changes are committed")
+		return nil
+	}
+
+	fmt.Printf("⚠️  Uncommitted task changes (%d):\n", len(report.Uncommitted))
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, u := range report.Uncommitted {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", u.Status, u.ID, u.Title)
+	}
+	return w.Flush()
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+}
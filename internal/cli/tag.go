@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/service"
+	"github.com/hamsa0x7/strand/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage task tags",
+	Long:  `Add or remove tags on tasks, or rename and merge tags across the project.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <id>... [--] <tag>...",
+	Short: "Add tags to tasks",
+	Long: `Add tags to tasks. Tags may be separate arguments or comma-separated.
+Tags a task already has are left alone.
+
+` + tagTargetsHelp,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, tags, err := tagTargets(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		results, err := bulkRun(ids, func(svc *service.Service, id string) (*core.Task, error) {
+			view, err := svc.Update(service.UpdateArgs{ID: id, AddTags: tags}, nil)
+			return view.Task, err
+		})
+		if err != nil {
+			return err
+		}
+		return printBulk(cmd, "Tagged", results)
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <id>... [--] <tag>...",
+	Short: "Remove tags from tasks",
+	Long: `Remove tags from tasks. Tags may be separate arguments or comma-separated.
+
+` + tagTargetsHelp,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, tags, err := tagTargets(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		results, err := bulkRun(ids, func(svc *service.Service, id string) (*core.Task, error) {
+			view, err := svc.Update(service.UpdateArgs{ID: id, RemoveTags: tags}, nil)
+			return view.Task, err
+		})
+		if err != nil {
+			return err
+		}
+		return printBulk(cmd, "Untagged", results)
+	},
+}
+
+var tagRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag on every task",
+	Long: `Rename a tag on every task that has it. Fails if the new tag is already
+in use; merge the tags instead.`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+		if err := validateTagName(to); err != nil {
+			return err
+		}
+		if from == to {
+			return fmt.Errorf("tag is already called '%s'", to)
+		}
+
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+		if len(tasksWithTag(tasks, to)) > 0 {
+			return fmt.Errorf("tag '%s' is already in use; use 'strand tag merge %s %s'", to, from, to)
+		}
+
+		return retag(cmd, tasks, []string{from}, to, "Renamed")
+	},
+}
+
+var tagMergeCmd = &cobra.Command{
+	Use:   "merge <tag>... <into>",
+	Short: "Merge tags into one",
+	Long: `Replace each of the given tags with the last one on every task that has
+them, e.g. 'strand tag merge ui front-end frontend'.`,
+	Args:        cobra.MinimumNArgs(2),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		into := strings.TrimSpace(args[len(args)-1])
+		if err := validateTagName(into); err != nil {
+			return err
+		}
+		var from []string
+		for _, tag := range args[:len(args)-1] {
+			if tag = strings.TrimSpace(tag); tag != into && !contains(from, tag) {
+				from = append(from, tag)
+			}
+		}
+		if len(from) == 0 {
+			return fmt.Errorf("nothing to merge into '%s'", into)
+		}
+
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+
+		return retag(cmd, tasks, from, into, "Merged")
+	},
+}
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List tags with task counts",
+	Long:  `List every tag in use with the number of tasks carrying it, most used first.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		counts, err := tagCounts()
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(counts, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(counts) == 0 {
+			fmt.Println("No tags found.")
+			return nil
+		}
+
+		// Padded by hand: color codes would throw tabwriter off
+		width := len("TAG")
+		for _, tc := range counts {
+			if n := len([]rune(tc.Tag)); n > width {
+				width = n
+			}
+		}
+		fmt.Printf("%-*s  %s\n", width, "TAG", "TASKS")
+		fmt.Printf("%-*s  %s\n", width, "───", "─────")
+		for _, tc := range counts {
+			pad := strings.Repeat(" ", width-len([]rune(tc.Tag)))
+			fmt.Printf("%s%s  %d\n", tagLabel(tc.Tag), pad, tc.Count)
+		}
+
+		fmt.Printf("\nTotal: %d tags\n", len(counts))
+		return nil
+	},
+}
+
+// tagTargetsHelp documents how tag add and remove tell IDs from tags
+const tagTargetsHelp = `The first argument is the task ID and the rest are tags. To tag several
+tasks, put -- between the IDs and the tags. Use - as an ID to read task IDs
+from stdin, or --where and only tags to act on every matching task.
+--dry-run shows what would change without saving, and --json prints one
+result per task.`
+
+// tagTargets splits tag add/remove arguments into task IDs and tags: with
+// --where every argument is a tag, with -- the IDs are the arguments before
+// it, and otherwise only the first argument is an ID
+func tagTargets(cmd *cobra.Command, args []string) ([]string, []string, error) {
+	var idArgs, tagArgs []string
+	switch dash := cmd.ArgsLenAtDash(); {
+	case bulkWhere != "":
+		if dash >= 0 {
+			return nil, nil, fmt.Errorf("pass task IDs or --where, not both")
+		}
+		tagArgs = args
+	case dash >= 0:
+		idArgs, tagArgs = args[:dash], args[dash:]
+	default:
+		idArgs, tagArgs = args[:1], args[1:]
+	}
+	if bulkWhere == "" && len(idArgs) == 0 {
+		return nil, nil, fmt.Errorf("pass at least one task ID before --")
+	}
+	if len(tagArgs) == 0 {
+		return nil, nil, fmt.Errorf("pass at least one tag after the task IDs")
+	}
+
+	tags, err := parseTagList(strings.Join(tagArgs, ","))
+	if err != nil {
+		return nil, nil, err
+	}
+	ids, err := bulkTargets(idArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ids, tags, nil
+}
+
+// retag replaces the tags in from with into on every task that has one of
+// them, and reports the results
+func retag(cmd *cobra.Command, tasks []*core.Task, from []string, into, verb string) error {
+	var ids []string
+	for _, tag := range from {
+		for _, task := range tasksWithTag(tasks, tag) {
+			if !contains(ids, task.ID) {
+				ids = append(ids, task.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("no tasks are tagged %s", strings.Join(from, " or "))
+	}
+	sort.Strings(ids)
+
+	// The service keeps the new tag where the first old one was
+	results, err := bulkRun(ids, func(svc *service.Service, id string) (*core.Task, error) {
+		view, err := svc.Update(service.UpdateArgs{ID: id, AddTags: []string{into}, RemoveTags: from}, nil)
+		return view.Task, err
+	})
+	if err != nil {
+		return err
+	}
+	if err := printBulk(cmd, verb, results); err != nil {
+		return err
+	}
+
+	if !outputJSON {
+		for _, tag := range from {
+			if _, ok := projectCfg.Tags.Colors[tag]; ok {
+				fmt.Printf("\nNote: tags.colors in .strand/config.yaml still lists '%s'\n", tag)
+			}
+		}
+	}
+	return nil
+}
+
+// tagCounts counts tags in the cache's task_tags table, or across every
+// task when the store has no cache
+func tagCounts() ([]storage.TagCount, error) {
+	if counter, ok := store.(storage.TagCounter); ok {
+		counts, err := counter.TagCounts()
+		if err != nil {
+			return nil, fmt.Errorf("failed to count tags: %w", err)
+		}
+		return counts, nil
+	}
+
+	tasks, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	byTag := make(map[string]int)
+	for _, task := range tasks {
+		for _, tag := range task.Tags {
+			byTag[tag]++
+		}
+	}
+
+	counts := []storage.TagCount{}
+	for tag, n := range byTag {
+		counts = append(counts, storage.TagCount{Tag: tag, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts, nil
+}
+
+// tasksWithTag returns the tasks carrying tag
+func tasksWithTag(tasks []*core.Task, tag string) []*core.Task {
+	var result []*core.Task
+	for _, task := range tasks {
+		if contains(task.Tags, tag) {
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
+// parseTagList splits a comma-separated tag argument, dropping blanks
+func parseTagList(arg string) ([]string, error) {
+	var tags []string
+	for _, tag := range strings.Split(arg, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !contains(tags, tag) {
+			if err := validateTagName(tag); err != nil {
+				return nil, err
+			}
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tags given")
+	}
+	return tags, nil
+}
+
+// validateTagName rejects tags that could not be written back as a single
+// list item
+func validateTagName(tag string) error {
+	if tag == "" || strings.ContainsAny(tag, ", \t\n") {
+		return fmt.Errorf("invalid tag '%s': tags cannot be empty or contain commas or spaces", tag)
+	}
+	return nil
+}
+
+// tagLabel renders a tag in its configured color
+func tagLabel(tag string) string {
+	if color := projectCfg.TagColor(tag); color != "" {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(tag)
+	}
+	return tag
+}
+
+// tagLabels renders a task's tags in their colors, comma-separated
+func tagLabels(tags []string) string {
+	labels := make([]string, len(tags))
+	for i, tag := range tags {
+		labels[i] = tagLabel(tag)
+	}
+	return strings.Join(labels, ", ")
+}
+
+func init() {
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	tagCmd.AddCommand(tagRenameCmd)
+	tagCmd.AddCommand(tagMergeCmd)
+
+	addBulkFlags(tagAddCmd)
+	addBulkFlags(tagRemoveCmd)
+	for _, cmd := range []*cobra.Command{tagRenameCmd, tagMergeCmd} {
+		cmd.Flags().BoolVar(&bulkDryRun, "dry-run", false, "Show what would change without saving")
+		cmd.Flags().BoolVar(&outputJSON, "json", false, "Output one result per task as JSON")
+	}
+
+	tagsCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+}
@@ -0,0 +1,427 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/hamsa0x7/strand/internal/graph"
+	"github.com/hamsa0x7/strand/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timeAgent   string
+	logTimeNote string
+	reportBy    string
+	reportSince string
+)
+
+// reportGroups lists the values accepted by time report --by
+var reportGroups = []string{"task", "epic", "assignee", "tag"}
+
+var startCmd = &cobra.Command{
+	Use:   "start <id>",
+	Short: "Start a work timer on a task",
+	Long: `Start a timer for the agent on a task. The session is recorded in the
+task file when 'strand stop' is called.
+
+The agent name defaults to $STRAND_AGENT, then $USER.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		agent := resolveAgent(timeAgent)
+		now := time.Now()
+
+		task, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		before := history.Snapshot(task)
+		if err := task.StartTimer(agent, now); err != nil {
+			return err
+		}
+		task.Updated = now
+
+		if err := store.Update(task); err != nil {
+			return fmt.Errorf("failed to start timer: %w", err)
+		}
+		recordChanges(agent, history.ActionTime, before, task)
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(task, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("✅ Started timer on task: %s\n", task.ID)
+		fmt.Printf("   Agent: %s\n", agent)
+		fmt.Printf("   Logged so far: %s\n", dates.FormatDuration(task.Logged()))
+
+		return nil
+	},
+}
+
+var stopCmd = &cobra.Command{
+	Use:   "stop [id]",
+	Short: "Stop a work timer and record the session",
+	Long: `Stop the agent's timer on a task and record the session in the task file.
+
+Without an ID, stops the agent's only running timer.`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		agent := resolveAgent(timeAgent)
+		now := time.Now()
+
+		id := ""
+		if len(args) > 0 {
+			id = args[0]
+		} else {
+			running, err := runningTimers(agent)
+			if err != nil {
+				return err
+			}
+			switch len(running) {
+			case 0:
+				return fmt.Errorf("no timer running for %s", agent)
+			case 1:
+				id = running[0]
+			default:
+				return fmt.Errorf("timers running for %s on %s; pass a task ID", agent, strings.Join(running, ", "))
+			}
+		}
+
+		task, err := store.Get(id)
+		if err != nil {
+			return err
+		}
+
+		before := history.Snapshot(task)
+		session, err := task.StopTimer(agent, now)
+		if err != nil {
+			return err
+		}
+		task.Updated = now
+
+		if err := store.Update(task); err != nil {
+			return fmt.Errorf("failed to stop timer: %w", err)
+		}
+		recordChanges(agent, history.ActionTime, before, task)
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(task, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("✅ Stopped timer on task: %s\n", task.ID)
+		fmt.Printf("   Session: %s\n", dates.FormatDuration(session))
+		fmt.Printf("   Logged: %s\n", formatEffort(task))
+
+		return nil
+	},
+}
+
+var logTimeCmd = &cobra.Command{
+	Use:   "log-time <id> <duration>",
+	Short: "Record time spent on a task",
+	Long: `Record a finished work session on a task, ending now.
+
+The duration is written like 1h30m, 45m or 1.5h.`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		agent := resolveAgent(timeAgent)
+
+		d, err := dates.ParseDuration(args[1])
+		if err != nil {
+			return err
+		}
+
+		task, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		before := history.Snapshot(task)
+		now := time.Now()
+		task.LogTime(agent, d, now, logTimeNote)
+		task.Updated = now
+
+		if err := store.Update(task); err != nil {
+			return fmt.Errorf("failed to log time: %w", err)
+		}
+		recordChanges(agent, history.ActionTime, before, task)
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(task, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("✅ Logged %s on task: %s\n", dates.FormatDuration(d), task.ID)
+		fmt.Printf("   Agent: %s\n", agent)
+		fmt.Printf("   Logged: %s\n", formatEffort(task))
+
+		return nil
+	},
+}
+
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Time tracking reports",
+	Long:  `Report the time logged with start/stop and log-time.`,
+}
+
+// timeRow is one line of a time report
+type timeRow struct {
+	Key      string        `json:"key"`
+	Title    string        `json:"title,omitempty"`
+	Logged   core.Duration `json:"logged"`
+	Minutes  int           `json:"logged_minutes"`
+	Estimate core.Duration `json:"estimate,omitempty"`
+	Sessions int           `json:"sessions"`
+	Running  bool          `json:"running,omitempty"`
+}
+
+var timeReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show time logged per task, epic, assignee or tag",
+	Long: `Total the time logged, grouped with --by:
+
+  task      each task, with its estimate
+  epic      the nearest epic above each task
+  assignee  the agent who logged the time
+  tag       each tag on the task
+
+Running timers count up to now. Use --since to only count sessions
+started after a date or age (yesterday, 2026-01-01, 7d).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !contains(reportGroups, reportBy) {
+			return fmt.Errorf("invalid --by '%s'. Valid: %s", reportBy, strings.Join(reportGroups, ", "))
+		}
+
+		now := time.Now()
+		var since time.Time
+		if reportSince != "" {
+			t, err := parseSince(reportSince, now)
+			if err != nil {
+				return err
+			}
+			since = t
+		}
+
+		tasks, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+
+		rows := timeReport(graph.TaskMap(tasks), reportBy, since, now)
+
+		if outputJSON {
+			if rows == nil {
+				rows = []*timeRow{}
+			}
+			data, _ := json.MarshalIndent(rows, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(rows) == 0 {
+			fmt.Println("No time logged.")
+			return nil
+		}
+
+		header := []string{strings.ToUpper(reportBy)}
+		if reportBy == "task" || reportBy == "epic" {
+			header = append(header, "TITLE")
+		}
+		header = append(header, "LOGGED", "ESTIMATE", "USED", "SESSIONS")
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		fmt.Fprintln(w, tableRule(header))
+
+		var total time.Duration
+		for _, row := range rows {
+			cols := []string{row.Key}
+			if reportBy == "task" || reportBy == "epic" {
+				cols = append(cols, row.Title)
+			}
+			logged := dates.FormatDuration(time.Duration(row.Logged))
+			if row.Running {
+				logged += " (running)"
+			}
+			estimate, used := "", ""
+			if row.Estimate > 0 {
+				estimate = dates.FormatDuration(time.Duration(row.Estimate))
+				used = fmt.Sprintf("%d%%", int(100*float64(row.Logged)/float64(row.Estimate)))
+			}
+			cols = append(cols, logged, estimate, used, fmt.Sprintf("%d", row.Sessions))
+			fmt.Fprintln(w, strings.Join(cols, "\t"))
+			total += time.Duration(row.Logged)
+		}
+
+		w.Flush()
+		if reportBy == "tag" {
+			// A task with several tags counts towards each
+			fmt.Printf("\nTotal: %d tags\n", len(rows))
+		} else {
+			fmt.Printf("\nTotal: %s logged\n", dates.FormatDuration(total))
+		}
+
+		return nil
+	},
+}
+
+// timeReport totals the sessions started at or after since, grouped by
+// by, most time first. Estimates are summed over the tasks in each group
+// that have time logged, unless an epic has its own.
+func timeReport(taskMap map[string]*core.Task, by string, since, now time.Time) []*timeRow {
+	groups := make(map[string]*timeRow)
+	estimated := make(map[string]bool)
+
+	for _, task := range taskMap {
+		for _, entry := range task.TimeLog {
+			if entry.Start.Before(since) {
+				continue
+			}
+			for _, key := range reportKeys(taskMap, task, entry, by) {
+				row, ok := groups[key]
+				if !ok {
+					row = &timeRow{Key: key, Title: reportTitle(taskMap, key, by)}
+					groups[key] = row
+				}
+				row.Logged += core.Duration(entry.Duration(now))
+				row.Sessions++
+				row.Running = row.Running || entry.Running()
+
+				if by != "assignee" && !estimated[key+"\x00"+task.ID] {
+					estimated[key+"\x00"+task.ID] = true
+					row.Estimate += task.Estimate
+				}
+			}
+		}
+	}
+
+	var rows []*timeRow
+	for _, row := range groups {
+		// An epic's own estimate covers its children
+		if epic, ok := taskMap[row.Key]; ok && by == "epic" && epic.Estimate > 0 {
+			row.Estimate = epic.Estimate
+		}
+		row.Minutes = int(time.Duration(row.Logged).Round(time.Minute) / time.Minute)
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Logged != rows[j].Logged {
+			return rows[i].Logged > rows[j].Logged
+		}
+		return rows[i].Key < rows[j].Key
+	})
+
+	return rows
+}
+
+// reportKeys returns the groups a session counts towards
+func reportKeys(taskMap map[string]*core.Task, task *core.Task, entry core.TimeEntry, by string) []string {
+	switch by {
+	case "epic":
+		return []string{epicOf(taskMap, task)}
+	case "assignee":
+		return []string{entry.Agent}
+	case "tag":
+		if len(task.Tags) == 0 {
+			return []string{"(untagged)"}
+		}
+		return task.Tags
+	default:
+		return []string{task.ID}
+	}
+}
+
+// reportTitle returns the title shown next to a task or epic key
+func reportTitle(taskMap map[string]*core.Task, key, by string) string {
+	if by != "task" && by != "epic" {
+		return ""
+	}
+	if task, ok := taskMap[key]; ok {
+		return task.Title
+	}
+	return ""
+}
+
+// epicOf returns the task itself if it is an epic, else its nearest epic
+// ancestor, or "(no epic)"
+func epicOf(taskMap map[string]*core.Task, task *core.Task) string {
+	if task.Type == core.TaskTypeEpic {
+		return task.ID
+	}
+	for _, ancestor := range graph.Ancestors(taskMap, task.ID) {
+		if ancestor.Type == core.TaskTypeEpic {
+			return ancestor.ID
+		}
+	}
+	return "(no epic)"
+}
+
+// runningTimers returns the IDs of tasks with a timer running for agent
+func runningTimers(agent string) ([]string, error) {
+	tasks, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	var ids []string
+	for _, task := range tasks {
+		if contains(task.RunningTimers(), agent) {
+			ids = append(ids, task.ID)
+		}
+	}
+	return ids, nil
+}
+
+// formatEffort describes logged time against the estimate, e.g.
+// "1h30m of 4h (37%)"
+func formatEffort(task *core.Task) string {
+	logged := task.Logged()
+	if task.Estimate == 0 {
+		return dates.FormatDuration(logged)
+	}
+	return fmt.Sprintf("%s of %s (%d%%)",
+		dates.FormatDuration(logged),
+		dates.FormatDuration(time.Duration(task.Estimate)),
+		int(100*float64(logged)/float64(task.Estimate)),
+	)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{startCmd, stopCmd, logTimeCmd} {
+		cmd.Flags().StringVar(&timeAgent, "agent", "", "Agent name (default $STRAND_AGENT or $USER)")
+		cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	}
+	logTimeCmd.Flags().StringVar(&logTimeNote, "note", "", "What the time was spent on")
+
+	timeReportCmd.Flags().StringVar(&reportBy, "by", "task", "Group by task, epic, assignee or tag")
+	timeReportCmd.Flags().StringVar(&reportSince, "since", "", "Only count sessions started after a date or age (7d, yesterday)")
+	timeReportCmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	timeCmd.AddCommand(timeReportCmd)
+}
@@ -1,10 +1,14 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/service"
 	"github.com/hamsa0x7/strand/internal/tui"
+	"github.com/hamsa0x7/strand/internal/watch"
 	"github.com/spf13/cobra"
 )
 
@@ -14,13 +18,36 @@ var tuiCmd = &cobra.Command{
 	Long:  `Launch the interactive terminal UI for managing tasks.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Create TUI model
-		m, err := tui.InitialModel(store)
+		// Changes go through the service, like the servers' writes: each
+		// takes the project lock, closes finished epics and is committed
+		svc := newService(core.CurrentAgent())
+		m, err := tui.InitialModel(store, func(id string, status core.TaskStatus) error {
+			_, err := svc.Update(service.UpdateArgs{ID: id, Status: status}, nil)
+			return err
+		}, projectCfg.TagColor)
 		if err != nil {
 			return fmt.Errorf("failed to initialize TUI: %w", err)
 		}
 
-		// Run TUI
+		// Run TUI, reloading whenever task files change
 		p := tea.NewProgram(m, tea.WithAltScreen())
+
+		watcher, err := newWatcher(0)
+		if err != nil {
+			return err
+		}
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		go watcher.Run(ctx, func(events []watch.Event) {
+			var msg tui.TasksChangedMsg
+			for _, e := range events {
+				if e.Type == watch.Deleted {
+					msg.Deleted = append(msg.Deleted, e.ID)
+				}
+			}
+			p.Send(msg)
+		})
+
 		if _, err := p.Run(); err != nil {
 			return fmt.Errorf("TUI error: %w", err)
 		}
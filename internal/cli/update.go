@@ -2,76 +2,111 @@ package cli
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/hamsa0x7/strand/internal/service"
 	"github.com/spf13/cobra"
 )
 
 var (
-	updateStatus   string
-	updatePriority string
-	updateAssignee string
+	updateStatus    string
+	updatePriority  string
+	updateAssignee  string
+	updateParent    string
+	updateAutoClose bool
+	updateDue       string
+	updateStart     string
+	updateEstimate  string
 )
 
 var updateCmd = &cobra.Command{
-	Use:   "update <id>",
-	Short: "Update a task",
-	Long:  `Update task fields like status, priority, or assignee.`,
-	Args:  cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		id := args[0]
+	Use:   "update <id>...",
+	Short: "Update one or more tasks",
+	Long: `Update task fields like status, priority, or assignee.
 
-		// Validate inputs
-		if updateStatus != "" {
-			if err := ValidateStatus(updateStatus); err != nil {
-				return err
-			}
+Use --parent to move a task under an epic, or --parent none to detach it.
+--due and --start accept today, tomorrow, friday, +3d, +2w or a date;
+none clears them. --estimate takes an effort such as 4h or 1h30m, or none.
+
+` + bulkHelp,
+	Args:        cobra.ArbitraryArgs,
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		update := service.UpdateArgs{
+			Status:   core.TaskStatus(updateStatus),
+			Priority: core.TaskPriority(updatePriority),
+			Assignee: updateAssignee,
+			Parent:   updateParent,
+			Due:      updateDue,
+			Start:    updateStart,
+			Estimate: updateEstimate,
 		}
-		if updatePriority != "" {
-			if err := ValidatePriority(updatePriority); err != nil {
-				return err
-			}
+		if cmd.Flags().Changed("auto-close") {
+			update.AutoClose = &updateAutoClose
 		}
 
-		// Get existing task
-		task, err := store.Get(id)
+		ids, err := bulkTargets(args)
 		if err != nil {
 			return err
 		}
 
-		// Apply updates
-		if updateStatus != "" {
-			task.Status = core.TaskStatus(updateStatus)
-		}
-		if updatePriority != "" {
-			task.Priority = core.TaskPriority(updatePriority)
-		}
-		if updateAssignee != "" {
-			task.Assignee = updateAssignee
-		}
-
-		// Update timestamp
-		task.Updated = time.Now()
-
-		// Save
-		if err := store.Update(task); err != nil {
-			return fmt.Errorf("failed to update task: %w", err)
+		results, err := bulkRun(ids, func(svc *service.Service, id string) (*core.Task, error) {
+			update.ID = id
+			view, err := svc.Update(update, nil)
+			return view.Task, err
+		})
+		if err != nil {
+			return err
 		}
 
-		fmt.Printf("✅ Updated task: %s\n", task.ID)
-		fmt.Printf("   Status: %s\n", task.Status)
-		fmt.Printf("   Priority: %s\n", task.Priority)
-		if task.Assignee != "" {
-			fmt.Printf("   Assignee: %s\n", task.Assignee)
+		if isSingleTarget(args) {
+			r := results[0]
+			if !r.OK {
+				return r.err
+			}
+			task := r.task
+			if task == nil {
+				task, err = store.Get(r.ID)
+				if err != nil {
+					return err
+				}
+			}
+			printUpdated(task)
+			printFollowUps(results)
+			return nil
 		}
 
-		return nil
+		err = printBulk(cmd, "Updated", results)
+		printFollowUps(results)
+		return err
 	},
 }
 
+// printUpdated prints the detailed result of updating a single task
+func printUpdated(task *core.Task) {
+	fmt.Printf("✅ Updated task: %s\n", task.ID)
+	fmt.Printf("   Status: %s\n", task.Status)
+	fmt.Printf("   Priority: %s\n", task.Priority)
+	if task.Assignee != "" {
+		fmt.Printf("   Assignee: %s\n", task.Assignee)
+	}
+	if task.Parent != "" {
+		fmt.Printf("   Parent: %s\n", task.Parent)
+	}
+	if task.Due != nil {
+		fmt.Printf("   Due: %s\n", dates.Format(*task.Due))
+	}
+}
+
 func init() {
-	updateCmd.Flags().StringVarP(&updateStatus, "status", "s", "", "Status (backlog|ready|in-progress|done|blocked|cancelled)")
+	updateCmd.Flags().StringVarP(&updateStatus, "status", "s", "", "Status, one of workflow.statuses in .strand/config.yaml")
 	updateCmd.Flags().StringVarP(&updatePriority, "priority", "p", "", "Priority (critical|high|medium|low)")
-	updateCmd.Flags().StringVarP(&updateAssignee, "assignee", "a", "", "Assignee")
+	updateCmd.Flags().StringVarP(&updateAssignee, "assignee", "a", "", "Assignee (none to unassign)")
+	updateCmd.Flags().StringVar(&updateParent, "parent", "", "Parent task or epic ID (none to detach)")
+	updateCmd.Flags().StringVar(&updateDue, "due", "", "Due date ("+dateHelp+", or none)")
+	updateCmd.Flags().StringVar(&updateStart, "start", "", "Start date ("+dateHelp+", or none)")
+	updateCmd.Flags().StringVar(&updateEstimate, "estimate", "", "Expected effort, e.g. 4h or 1h30m (or none)")
+	updateCmd.Flags().BoolVar(&updateAutoClose, "auto-close", false, "Mark done automatically when all children are done")
+	addBulkFlags(updateCmd)
 }
@@ -6,42 +6,14 @@ import (
 	"github.com/hamsa0x7/strand/internal/core"
 )
 
-// ValidateStatus validates a status string
+// ValidateStatus validates a status string against the project workflow
 func ValidateStatus(status string) error {
-	validStatuses := []core.TaskStatus{
-		core.TaskStatusBacklog,
-		core.TaskStatusReady,
-		core.TaskStatusInProgress,
-		core.TaskStatusDone,
-		core.TaskStatusBlocked,
-		core.TaskStatusCancelled,
+	workflow := core.CurrentWorkflow()
+	if workflow.IsValid(core.TaskStatus(status)) {
+		return nil
 	}
 
-	for _, valid := range validStatuses {
-		if status == string(valid) {
-			return nil
-		}
-	}
-
-	return fmt.Errorf("invalid status '%s'. Valid: backlog, ready, in-progress, done, blocked, cancelled", status)
-}
-
-// ValidatePriority validates a priority string
-func ValidatePriority(priority string) error {
-	validPriorities := []core.TaskPriority{
-		core.TaskPriorityCritical,
-		core.TaskPriorityHigh,
-		core.TaskPriorityMedium,
-		core.TaskPriorityLow,
-	}
-
-	for _, valid := range validPriorities {
-		if priority == string(valid) {
-			return nil
-		}
-	}
-
-	return fmt.Errorf("invalid priority '%s'. Valid: critical, high, medium, low", priority)
+	return fmt.Errorf("invalid status '%s'. Valid: %s", status, workflow.StatusList())
 }
 
 // ValidateType validates a task type string
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/markdown"
+	"github.com/hamsa0x7/strand/internal/storage"
+	"github.com/hamsa0x7/strand/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var watchInterval time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print a JSON line for every change to a task",
+	Long: `Follow the task files and print one JSON object per change, whoever
+makes it: strand, an editor or a git pull.
+
+  {"type":"updated","id":"strand-a1b2c3d4","path":".../strand-a1b2c3d4.md",
+   "time":"...","task":{...},"changes":["status: ready → in-progress"]}
+
+type is created, updated, deleted, or invalid when a file stops parsing
+(error says why). Bursts of writes are reported once they settle.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		watcher, err := newWatcher(watchInterval)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		enc := json.NewEncoder(os.Stdout)
+		var encodeErr error
+		err = watcher.Run(ctx, func(events []watch.Event) {
+			for _, event := range events {
+				if encodeErr == nil {
+					encodeErr = enc.Encode(event)
+				}
+			}
+			if encodeErr != nil {
+				stop() // Stdout is gone, e.g. a closed pipe
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return encodeErr
+	},
+}
+
+// newWatcher creates a watcher over the project's task files that keeps
+// the cache current. interval 0 uses the default.
+func newWatcher(interval time.Duration) (*watch.Watcher, error) {
+	md := markdown.NewStore()
+	if err := md.Init(strandDir); err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	opts := watch.Options{Interval: interval, Load: md.Load}
+	if refresher, ok := store.(storage.Refresher); ok {
+		opts.Refresh = refresher.Refresh
+	}
+	return watch.New(md.TasksDir(), opts), nil
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 0, "How often to check the files (default 250ms)")
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/query"
+	"github.com/hamsa0x7/strand/internal/storage"
+)
+
+// whereHelp documents the --where flag for every command that accepts it
+const whereHelp = `Filter expression, e.g. 'status:in-progress AND (tag:auth OR priority>=high) AND assignee:me AND updated>7d'`
+
+// queryEnv returns the environment --where expressions are evaluated in
+func queryEnv() query.Env {
+	return query.Env{
+		Now: time.Now(),
+		Me:  core.CurrentAgent(),
+	}
+}
+
+// parseWhere parses a --where expression, returning nil if it is empty
+func parseWhere(expr string) (*query.Query, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	q, err := query.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --where expression: %w", err)
+	}
+	return q, nil
+}
+
+// whereTasks returns the tasks matching expr, or all tasks if it is empty.
+// Stores that support it evaluate the expression natively.
+func whereTasks(expr string) ([]*core.Task, error) {
+	q, err := parseWhere(expr)
+	if err != nil {
+		return nil, err
+	}
+	if q == nil {
+		return store.List()
+	}
+
+	if querier, ok := store.(storage.Querier); ok {
+		return querier.Where(q, queryEnv())
+	}
+
+	tasks, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	return q.Filter(tasks, queryEnv()), nil
+}
+
+// filterWhere filters already-loaded tasks by expr in memory
+func filterWhere(tasks []*core.Task, expr string) ([]*core.Task, error) {
+	q, err := parseWhere(expr)
+	if err != nil {
+		return nil, err
+	}
+	if q == nil {
+		return tasks, nil
+	}
+	return q.Filter(tasks, queryEnv()), nil
+}
@@ -0,0 +1,347 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the config file inside .strand/
+const FileName = "config.yaml"
+
+// Config is the project configuration stored in .strand/config.yaml
+type Config struct {
+	Workflow WorkflowConfig `yaml:"workflow"`
+	Ready    ReadyConfig    `yaml:"ready"`
+	Tags     TagsConfig     `yaml:"tags"`
+	Git      GitConfig      `yaml:"git"`
+}
+
+// WorkflowConfig defines task statuses and the rules for moving between
+// them. Omitted fields keep their defaults.
+type WorkflowConfig struct {
+	Statuses    []string            `yaml:"statuses,omitempty"`
+	Initial     string              `yaml:"initial,omitempty"`
+	Done        string              `yaml:"done,omitempty"`
+	Closed      []string            `yaml:"closed,omitempty"`
+	Ready       string              `yaml:"ready,omitempty"`
+	Working     string              `yaml:"working,omitempty"`
+	Transitions map[string][]string `yaml:"transitions,omitempty"`
+	Guards      map[string][]string `yaml:"guards,omitempty"`
+	AutoReady   bool                `yaml:"auto_ready,omitempty"`
+}
+
+// ReadyConfig controls how the ready queue is ranked
+type ReadyConfig struct {
+	Weights WeightsConfig `yaml:"weights"`
+}
+
+// WeightsConfig holds the ready score weights. Omitted weights keep their
+// defaults; set one to 0 to ignore that factor.
+type WeightsConfig struct {
+	Priority float64 `yaml:"priority"`
+	Due      float64 `yaml:"due"`
+	Age      float64 `yaml:"age"`
+	Unblocks float64 `yaml:"unblocks"`
+	Assigned float64 `yaml:"assigned"`
+}
+
+// TagsConfig controls how tags are displayed
+type TagsConfig struct {
+	// Colors maps a tag to a color name, an ANSI color number or #rrggbb
+	Colors map[string]string `yaml:"colors,omitempty"`
+}
+
+// GitConfig controls the git integration
+type GitConfig struct {
+	// AutoCommit commits the task files every change touches, with a
+	// message describing it
+	AutoCommit bool `yaml:"auto_commit,omitempty"`
+}
+
+// colorNames are the color names accepted in tags.colors, as ANSI numbers
+var colorNames = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+	"grey":    "8",
+}
+
+// TagColor returns the configured color for a tag as an ANSI color number
+// or #rrggbb, or "" if it has none
+func (c *Config) TagColor(tag string) string {
+	color, _ := parseColor(c.Tags.Colors[tag])
+	return color
+}
+
+// parseColor normalizes a color name, ANSI number 0-255 or #rgb/#rrggbb
+func parseColor(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	if n, ok := colorNames[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return s, nil
+	}
+	if strings.HasPrefix(s, "#") && (len(s) == 4 || len(s) == 7) {
+		if _, err := strconv.ParseUint(s[1:], 16, 32); err == nil {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid color '%s': use a name such as red, an ANSI number 0-255 or #rrggbb", s)
+}
+
+// ScoreWeights converts the weights for core
+func (c *Config) ScoreWeights() core.ScoreWeights {
+	w := c.Ready.Weights
+	return core.ScoreWeights{
+		Priority: w.Priority,
+		Due:      w.Due,
+		Age:      w.Age,
+		Unblocks: w.Unblocks,
+		Assigned: w.Assigned,
+	}
+}
+
+// Template is written to .strand/config.yaml by strand init
+const Template = `# Strand project configuration
+
+workflow:
+  # Every status a task can be in
+  statuses: [backlog, ready, in-progress, blocked, done, cancelled]
+
+  # Status of newly created tasks
+  initial: backlog
+
+  # Status that counts as finished work (used by epic rollups and auto-close)
+  done: done
+
+  # Statuses that no longer block dependent tasks
+  closed: [done, cancelled]
+
+  # Status released claims and auto_ready move tasks to
+  ready: ready
+
+  # Status 'strand claim' moves tasks to
+  working: in-progress
+
+  # Allowed moves. A status without an entry may move to any status.
+  # transitions:
+  #   backlog: [ready, in-progress, cancelled]
+  #   ready: [in-progress, blocked, backlog, cancelled]
+  #   in-progress: [done, blocked, ready, cancelled]
+  #   blocked: [ready, in-progress, cancelled]
+  #   done: [ready]
+  #   cancelled: [backlog]
+
+  # Preconditions for entering a status:
+  #   deps-closed      all dependencies are closed
+  #   children-closed  all child tasks are closed
+  guards:
+    in-progress: [deps-closed]
+    done: [deps-closed]
+
+  # Move backlog and blocked tasks to ready when their last open
+  # dependency is closed by 'strand update'
+  auto_ready: false
+
+ready:
+  # 'strand ready' and 'strand next' rank tasks by the sum of weight × factor
+  weights:
+    priority: 10   # critical 3, high 2, medium 1, low 0
+    due: 5         # 0 two weeks out, rising to 1 on the due date
+    age: 1         # per week since creation, up to 4
+    unblocks: 3    # per open task waiting on it
+    assigned: -5   # once, if it already has an assignee
+
+tags:
+  # Tag colors in 'strand list', 'strand tags' and the TUI: a name (red,
+  # green, yellow, blue, magenta, cyan, white, gray), an ANSI number 0-255
+  # or #rrggbb
+  # colors:
+  #   bug: red
+  #   frontend: "#61afef"
+
+git:
+  # Commit changed task files after every command that changes them, with
+  # messages like "strand: update strand-a1b2c3d4 status ready→done"
+  auto_commit: false
+`
+
+// Default returns the built-in configuration
+func Default() *Config {
+	w := core.DefaultWorkflow()
+
+	guards := make(map[string][]string)
+	for status, gs := range w.Guards {
+		for _, g := range gs {
+			guards[string(status)] = append(guards[string(status)], string(g))
+		}
+	}
+
+	weights := core.DefaultScoreWeights()
+
+	return &Config{
+		Workflow: WorkflowConfig{
+			Statuses: statusStrings(w.Statuses),
+			Initial:  string(w.Initial),
+			Done:     string(w.Done),
+			Closed:   statusStrings(w.Closed),
+			Ready:    string(w.Ready),
+			Working:  string(w.Working),
+			Guards:   guards,
+		},
+		Ready: ReadyConfig{
+			Weights: WeightsConfig{
+				Priority: weights.Priority,
+				Due:      weights.Due,
+				Age:      weights.Age,
+				Unblocks: weights.Unblocks,
+				Assigned: weights.Assigned,
+			},
+		},
+	}
+}
+
+// Load reads the config from strandDir, returning the defaults if the file
+// does not exist
+func Load(strandDir string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Join(strandDir, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	return Parse(data)
+}
+
+// Parse parses config YAML, filling omitted fields with defaults
+func Parse(data []byte) (*Config, error) {
+	// Omitted weights keep their defaults
+	cfg := Config{Ready: Default().Ready}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+
+	def := Default().Workflow
+	w := &cfg.Workflow
+	if len(w.Statuses) == 0 {
+		w.Statuses = def.Statuses
+	}
+	if w.Initial == "" {
+		w.Initial = w.Statuses[0]
+	}
+	if w.Done == "" {
+		w.Done = def.Done
+	}
+	if w.Closed == nil {
+		w.Closed = []string{w.Done}
+		if containsString(w.Statuses, string(core.TaskStatusCancelled)) {
+			w.Closed = append(w.Closed, string(core.TaskStatusCancelled))
+		}
+	}
+	// The ready and working roles default to the statuses of that name
+	if w.Ready == "" && containsString(w.Statuses, def.Ready) {
+		w.Ready = def.Ready
+	}
+	if w.Working == "" && containsString(w.Statuses, def.Working) {
+		w.Working = def.Working
+	}
+	if w.Guards == nil {
+		// Keep the default guards a custom workflow has statuses for
+		w.Guards = make(map[string][]string)
+		for status, guards := range def.Guards {
+			if containsString(w.Statuses, status) {
+				w.Guards[status] = guards
+			}
+		}
+	}
+
+	if _, err := cfg.BuildWorkflow(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+
+	for tag, color := range cfg.Tags.Colors {
+		if _, err := parseColor(color); err != nil {
+			return nil, fmt.Errorf("invalid %s: tags.colors.%s: %w", FileName, tag, err)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// BuildWorkflow converts the workflow section into a validated core.Workflow
+func (c *Config) BuildWorkflow() (*core.Workflow, error) {
+	w := &core.Workflow{
+		Statuses:    toStatuses(c.Workflow.Statuses),
+		Initial:     core.TaskStatus(c.Workflow.Initial),
+		Done:        core.TaskStatus(c.Workflow.Done),
+		Closed:      toStatuses(c.Workflow.Closed),
+		Ready:       core.TaskStatus(c.Workflow.Ready),
+		Working:     core.TaskStatus(c.Workflow.Working),
+		Transitions: make(map[core.TaskStatus][]core.TaskStatus),
+		Guards:      make(map[core.TaskStatus][]core.Guard),
+		AutoReady:   c.Workflow.AutoReady,
+	}
+
+	for from, tos := range c.Workflow.Transitions {
+		w.Transitions[core.TaskStatus(from)] = toStatuses(tos)
+	}
+	for status, guards := range c.Workflow.Guards {
+		for _, g := range guards {
+			w.Guards[core.TaskStatus(status)] = append(w.Guards[core.TaskStatus(status)], core.Guard(g))
+		}
+	}
+
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func toStatuses(list []string) []core.TaskStatus {
+	statuses := make([]core.TaskStatus, len(list))
+	for i, s := range list {
+		statuses[i] = core.TaskStatus(s)
+	}
+	return statuses
+}
+
+func statusStrings(list []core.TaskStatus) []string {
+	names := make([]string, len(list))
+	for i, s := range list {
+		names[i] = string(s)
+	}
+	return names
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
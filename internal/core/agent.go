@@ -0,0 +1,16 @@
+package core
+
+import (
+	"os"
+)
+
+// CurrentAgent returns the name of the human or agent running strand,
+// taken from $STRAND_AGENT, then $USER or $USERNAME
+func CurrentAgent() string {
+	for _, key := range []string{"STRAND_AGENT", "USER", "USERNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,28 @@
+package core
+
+// OpenDependencies returns the IDs of t's dependencies that are not closed.
+// Missing dependencies are ignored, as in IsReady.
+func (t *Task) OpenDependencies(allTasks map[string]*Task) []string {
+	var open []string
+	for _, depID := range t.DependsOn {
+		if dep, ok := allTasks[depID]; ok && !IsClosed(dep.Status) {
+			open = append(open, depID)
+		}
+	}
+	return open
+}
+
+// EffectiveStatus is the status to show for t: blocked while it is open and
+// waiting on an open dependency, whatever its stored status says
+func (t *Task) EffectiveStatus(allTasks map[string]*Task) TaskStatus {
+	if IsClosed(t.Status) || len(t.OpenDependencies(allTasks)) == 0 {
+		return t.Status
+	}
+	return TaskStatusBlocked
+}
+
+// IsDependencyBlocked reports whether t is blocked only because of open
+// dependencies, rather than having been marked blocked
+func (t *Task) IsDependencyBlocked(allTasks map[string]*Task) bool {
+	return t.Status != TaskStatusBlocked && t.EffectiveStatus(allTasks) == TaskStatusBlocked
+}
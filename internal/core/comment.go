@@ -0,0 +1,27 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// Comment is a note left on a task by a human or agent
+type Comment struct {
+	Author  string    `json:"author"`
+	Created time.Time `json:"created"`
+	Body    string    `json:"body"`
+}
+
+// NewComment creates a comment by author at the current time
+func NewComment(author, body string) Comment {
+	return Comment{
+		Author:  author,
+		Created: time.Now(),
+		Body:    strings.TrimSpace(body),
+	}
+}
+
+// AddComment appends a comment to the task
+func (t *Task) AddComment(c Comment) {
+	t.Comments = append(t.Comments, c)
+}
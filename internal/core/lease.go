@@ -0,0 +1,99 @@
+package core
+
+import (
+	"time"
+)
+
+// HasLease reports whether the task has been claimed with a lease
+func (t *Task) HasLease() bool {
+	return t.LeaseExpires != nil
+}
+
+// LeaseExpired reports whether the task's lease has run out at now
+func (t *Task) LeaseExpired(now time.Time) bool {
+	return t.LeaseExpires != nil && !now.Before(*t.LeaseExpires)
+}
+
+// IsClaimable returns true if an agent may claim the task: it is in the
+// workflow's initial or ready status with no open dependencies, and nobody
+// holds a live lease on it
+func (t *Task) IsClaimable(allTasks map[string]*Task, now time.Time) bool {
+	w := CurrentWorkflow()
+	if w.Working == "" || !t.IsReady(allTasks) {
+		return false
+	}
+
+	switch t.Status {
+	case w.Working:
+		// Work abandoned by a crashed agent goes back to the pool
+		return t.LeaseExpired(now)
+	case w.Initial, w.Ready:
+		return !t.HasLease() || t.LeaseExpired(now)
+	default:
+		return false
+	}
+}
+
+// SetStatus moves the task to status. Closing a task ends its lease, so a
+// finished task is never reclaimed. Moving a claimed task anywhere else out
+// of the working status drops the claim, as Release does, so it can be
+// claimed again straight away.
+func (t *Task) SetStatus(status TaskStatus) {
+	w := CurrentWorkflow()
+	switch {
+	case w.IsClosed(status):
+		t.LeaseExpires = nil
+	case t.HasLease() && t.Status == w.Working && status != w.Working:
+		t.Assignee = ""
+		t.LeaseExpires = nil
+	}
+	t.Status = status
+}
+
+// Claim assigns the task to agent, moves it to the workflow's working
+// status and starts a lease of the given length
+func (t *Task) Claim(agent string, lease time.Duration, now time.Time) {
+	expires := now.Add(lease)
+	t.Assignee = agent
+	t.Status = CurrentWorkflow().Working
+	t.LeaseExpires = &expires
+	t.Updated = now
+}
+
+// Renew extends the lease so it expires lease from now
+func (t *Task) Renew(lease time.Duration, now time.Time) {
+	expires := now.Add(lease)
+	t.LeaseExpires = &expires
+	t.Updated = now
+}
+
+// Release drops the claim and returns the task to the workflow's ready
+// status, or its initial status if it has none
+func (t *Task) Release(now time.Time) {
+	w := CurrentWorkflow()
+	t.Assignee = ""
+	t.LeaseExpires = nil
+	if t.Status == w.Working {
+		t.Status = w.Ready
+		if t.Status == "" {
+			t.Status = w.Initial
+		}
+	}
+	t.Updated = now
+}
+
+// PriorityRank orders priorities from most (0) to least urgent
+func PriorityRank(p TaskPriority) int {
+	switch p {
+	case TaskPriorityCritical:
+		return 0
+	case TaskPriorityHigh:
+		return 1
+	case TaskPriorityMedium:
+		return 2
+	case TaskPriorityLow:
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,28 @@
+package core
+
+import (
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/dates"
+)
+
+// IsOverdue reports whether an open task's due day has passed at now
+func (t *Task) IsOverdue(now time.Time) bool {
+	return t.Due != nil && !IsClosed(t.Status) && t.Due.Before(dates.Day(now))
+}
+
+// IsDueWithin reports whether an open task is due between today and the
+// given number of days from now, inclusive
+func (t *Task) IsDueWithin(now time.Time, days int) bool {
+	if t.Due == nil || IsClosed(t.Status) {
+		return false
+	}
+	today := dates.Day(now)
+	return !t.Due.Before(today) && t.Due.Before(today.AddDate(0, 0, days+1))
+}
+
+// HasStarted reports whether the task's start day has arrived at now.
+// Tasks without a start date have always started.
+func (t *Task) HasStarted(now time.Time) bool {
+	return t.Start == nil || !t.Start.After(now)
+}
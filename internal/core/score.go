@@ -0,0 +1,92 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/dates"
+)
+
+// ScoreWeights weigh the factors that rank the ready queue. A task's score
+// is the sum of each weight times its factor.
+type ScoreWeights struct {
+	Priority float64 // Per priority step above low: critical 3, high 2, medium 1, low 0
+	Due      float64 // Rises from 0 two weeks before the due date to 1 on it and after
+	Age      float64 // Per week since creation, up to 4
+	Unblocks float64 // Per open task waiting on it, directly or transitively
+	Assigned float64 // Applied once if it already has an assignee
+}
+
+// DefaultScoreWeights returns the built-in ranking
+func DefaultScoreWeights() ScoreWeights {
+	return ScoreWeights{
+		Priority: 10,
+		Due:      5,
+		Age:      1,
+		Unblocks: 3,
+		Assigned: -5,
+	}
+}
+
+// ScoreFactors are a task's inputs to its score
+type ScoreFactors struct {
+	Priority float64 `json:"priority"`
+	Due      float64 `json:"due"`
+	Age      float64 `json:"age"`
+	Unblocks int     `json:"unblocks"`
+	Assigned bool    `json:"assigned"`
+}
+
+// dueHorizon is how many days ahead a due date starts to count
+const dueHorizon = 14
+
+// maxAgeWeeks caps the age factor so old tasks do not swamp priority
+const maxAgeWeeks = 4
+
+// Factors computes a task's score inputs at now. blocks is the number of
+// open tasks waiting on it.
+func (t *Task) Factors(now time.Time, blocks int) ScoreFactors {
+	f := ScoreFactors{
+		Unblocks: blocks,
+		Assigned: t.Assignee != "",
+	}
+
+	if rank := PriorityRank(t.Priority); rank <= 3 {
+		f.Priority = float64(3 - rank)
+	}
+
+	if t.Due != nil {
+		days := dates.DaysBetween(now, *t.Due)
+		switch {
+		case days <= 0:
+			f.Due = 1
+		case days < dueHorizon:
+			f.Due = round2(1 - float64(days)/dueHorizon)
+		}
+	}
+
+	if !t.Created.IsZero() {
+		weeks := now.Sub(t.Created).Hours() / (24 * 7)
+		if weeks > maxAgeWeeks {
+			weeks = maxAgeWeeks
+		}
+		if weeks > 0 {
+			f.Age = round2(weeks)
+		}
+	}
+
+	return f
+}
+
+// Score combines the factors with the weights
+func (w ScoreWeights) Score(f ScoreFactors) float64 {
+	score := w.Priority*f.Priority + w.Due*f.Due + w.Age*f.Age + w.Unblocks*float64(f.Unblocks)
+	if f.Assigned {
+		score += w.Assigned
+	}
+	return round2(score)
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
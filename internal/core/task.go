@@ -1,6 +1,12 @@
 package core
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -45,39 +51,81 @@ type Task struct {
 	Title       string       `yaml:"-" json:"title"` // Extracted from markdown
 	Description string       `yaml:"-" json:"description"` // Markdown body
 	DependsOn   []string     `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	Parent      string       `yaml:"parent,omitempty" json:"parent,omitempty"`         // Epic or task this one belongs to
+	AutoClose   bool         `yaml:"auto_close,omitempty" json:"auto_close,omitempty"` // Close when all children are done
 	Assignee    string       `yaml:"assignee,omitempty" json:"assignee,omitempty"`
 	Created     time.Time    `yaml:"created" json:"created"`
 	Updated     time.Time    `yaml:"updated" json:"updated"`
+	Due         *time.Time   `yaml:"due,omitempty" json:"due,omitempty"`     // Deadline, a local calendar day
+	Start       *time.Time   `yaml:"start,omitempty" json:"start,omitempty"` // Not to be started before this day
 	Tags        []string     `yaml:"tags,omitempty" json:"tags,omitempty"`
+	LeaseExpires *time.Time  `yaml:"lease_expires,omitempty" json:"lease_expires,omitempty"` // Set while an agent holds a claim
+	Comments    []Comment    `yaml:"-" json:"comments,omitempty"` // Discussion, from the ## Comments section
+	Estimate    Duration     `yaml:"-" json:"estimate,omitempty"` // Expected effort
+	TimeLog     []TimeEntry  `yaml:"-" json:"time_log,omitempty"` // Work sessions, see start/stop and log-time
 	FilePath    string       `yaml:"-" json:"file_path"` // Path to markdown file
+	Version     string       `yaml:"-" json:"-"`         // Content hash of the file as last read or written
 }
 
 // NewTask creates a new task with defaults
-func NewTask(title string, taskType TaskType) *Task {
+func NewTask(title string, taskType TaskType) (*Task, error) {
+	id, err := NewID(title)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now()
 	return &Task{
-		ID:       generateID(),
+		ID:       id,
 		Type:     taskType,
-		Status:   TaskStatusBacklog,
+		Status:   CurrentWorkflow().Initial,
 		Priority: TaskPriorityMedium,
 		Title:    title,
 		Created:  now,
 		Updated:  now,
 		Tags:     []string{},
 		DependsOn: []string{},
+	}, nil
+}
+
+// IDPrefix is prepended to every generated task ID
+const IDPrefix = "strand-"
+
+// idHashLength is the number of hex characters kept from the ID hash
+const idHashLength = 8
+
+// NewID creates a unique hash-based ID. Each call returns a fresh ID, so
+// callers can retry with another one if it is already taken.
+func NewID(title string) (string, error) {
+	// Mix the title, a nanosecond timestamp and random bytes so that
+	// concurrent agents creating tasks in the same second never collide
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate task ID: %w", err)
 	}
+
+	h := sha256.New()
+	h.Write([]byte(title))
+	h.Write([]byte(strconv.FormatInt(time.Now().UnixNano(), 10)))
+	h.Write(nonce)
+
+	return IDPrefix + hex.EncodeToString(h.Sum(nil))[:idHashLength], nil
 }
 
-// generateID creates a unique hash-based ID
-func generateID() string {
-	// Simple implementation for MVP - timestamp-based
-	// TODO: Use hash-based IDs for multi-agent safety
-	return time.Now().Format("strand-20060102150405")
+// MatchesIDPrefix reports whether id starts with prefix, with or without
+// the "strand-" prefix (so both "a3f9" and "strand-a3f9" match)
+func MatchesIDPrefix(id, prefix string) bool {
+	if prefix == "" {
+		return false
+	}
+	if strings.HasPrefix(id, prefix) {
+		return true
+	}
+	return strings.HasPrefix(strings.TrimPrefix(id, IDPrefix), prefix)
 }
 
-// IsReady returns true if the task has no unresolved dependencies
+// IsReady returns true if the task is open and every dependency is closed
 func (t *Task) IsReady(allTasks map[string]*Task) bool {
-	if t.Status == TaskStatusDone || t.Status == TaskStatusCancelled {
+	if IsClosed(t.Status) {
 		return false
 	}
 	
@@ -86,7 +134,7 @@ func (t *Task) IsReady(allTasks map[string]*Task) bool {
 		if !exists {
 			continue // Missing dependency, ignore for now
 		}
-		if depTask.Status != TaskStatusDone {
+		if !IsClosed(depTask.Status) {
 			return false // Dependency not complete
 		}
 	}
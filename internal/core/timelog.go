@@ -0,0 +1,117 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/dates"
+)
+
+// Duration is an effort estimate, written as 1h30m in files and JSON
+type Duration time.Duration
+
+// MarshalText renders the duration as 1h30m
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(dates.FormatDuration(time.Duration(d))), nil
+}
+
+// UnmarshalText parses a duration such as 1h30m
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := dates.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// TimeEntry is a work session on a task. End is nil while the timer runs.
+type TimeEntry struct {
+	Agent string     `json:"agent"`
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+	Note  string     `json:"note,omitempty"`
+}
+
+// Duration returns the length of the session, counting a running session
+// up to now
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	if e.End != nil {
+		return e.End.Sub(e.Start)
+	}
+	return now.Sub(e.Start)
+}
+
+// Running reports whether the session's timer is still going
+func (e TimeEntry) Running() bool {
+	return e.End == nil
+}
+
+// Logged returns the total time of finished sessions
+func (t *Task) Logged() time.Duration {
+	var total time.Duration
+	for _, e := range t.TimeLog {
+		if !e.Running() {
+			total += e.Duration(time.Time{})
+		}
+	}
+	return total
+}
+
+// LoggedAt returns the total time logged, counting running timers up to now
+func (t *Task) LoggedAt(now time.Time) time.Duration {
+	var total time.Duration
+	for _, e := range t.TimeLog {
+		total += e.Duration(now)
+	}
+	return total
+}
+
+// RunningTimers returns the agents with a timer running on the task, sorted
+func (t *Task) RunningTimers() []string {
+	var agents []string
+	for _, e := range t.TimeLog {
+		if e.Running() {
+			agents = append(agents, e.Agent)
+		}
+	}
+	sort.Strings(agents)
+	return agents
+}
+
+// StartTimer opens a work session for agent
+func (t *Task) StartTimer(agent string, now time.Time) error {
+	for _, e := range t.TimeLog {
+		if e.Running() && e.Agent == agent {
+			return fmt.Errorf("timer already running on %s for %s since %s", t.ID, agent, e.Start.Format("15:04"))
+		}
+	}
+	t.TimeLog = append(t.TimeLog, TimeEntry{Agent: agent, Start: now})
+	return nil
+}
+
+// StopTimer closes agent's running session and returns its length
+func (t *Task) StopTimer(agent string, now time.Time) (time.Duration, error) {
+	for i := range t.TimeLog {
+		e := &t.TimeLog[i]
+		if e.Running() && e.Agent == agent {
+			end := now
+			e.End = &end
+			return e.Duration(now), nil
+		}
+	}
+	return 0, fmt.Errorf("no timer running on %s for %s", t.ID, agent)
+}
+
+// LogTime records a finished session of length d ending at now
+func (t *Task) LogTime(agent string, d time.Duration, now time.Time, note string) {
+	end := now
+	t.TimeLog = append(t.TimeLog, TimeEntry{
+		Agent: agent,
+		Start: now.Add(-d),
+		End:   &end,
+		Note:  strings.TrimSpace(note),
+	})
+}
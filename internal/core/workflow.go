@@ -0,0 +1,276 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Guard is a precondition for moving a task into a status
+type Guard string
+
+const (
+	// GuardDepsClosed requires every dependency to be closed
+	GuardDepsClosed Guard = "deps-closed"
+
+	// GuardChildrenClosed requires every child task to be closed
+	GuardChildrenClosed Guard = "children-closed"
+)
+
+// Guards lists the known guards
+var Guards = []Guard{GuardDepsClosed, GuardChildrenClosed}
+
+// Workflow defines the statuses a task can be in and how it may move
+// between them
+type Workflow struct {
+	Statuses []TaskStatus // All valid statuses, in display order
+	Initial  TaskStatus   // Status of newly created tasks
+	Done     TaskStatus   // Status that counts as completed work
+	Closed   []TaskStatus // Statuses that no longer block dependents
+
+	// Ready is where work waits to be picked up: released claims return to
+	// it and auto_ready moves tasks to it. Empty if the workflow has none.
+	Ready TaskStatus
+
+	// Working is the status claimed tasks move to. Empty if the workflow
+	// has none, which turns claiming off.
+	Working TaskStatus
+
+	// Transitions maps a status to the statuses it may move to. A status
+	// without an entry may move anywhere.
+	Transitions map[TaskStatus][]TaskStatus
+
+	// Guards maps a status to the preconditions for entering it
+	Guards map[TaskStatus][]Guard
+
+	// AutoReady moves backlog and blocked dependents to ready once their
+	// last open dependency is closed
+	AutoReady bool
+}
+
+// DefaultWorkflow returns the built-in workflow: the six standard statuses,
+// any transition allowed, and open dependencies guarding in-progress and done
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []TaskStatus{
+			TaskStatusBacklog,
+			TaskStatusReady,
+			TaskStatusInProgress,
+			TaskStatusBlocked,
+			TaskStatusDone,
+			TaskStatusCancelled,
+		},
+		Initial:     TaskStatusBacklog,
+		Done:        TaskStatusDone,
+		Closed:      []TaskStatus{TaskStatusDone, TaskStatusCancelled},
+		Ready:       TaskStatusReady,
+		Working:     TaskStatusInProgress,
+		Transitions: map[TaskStatus][]TaskStatus{},
+		Guards: map[TaskStatus][]Guard{
+			TaskStatusInProgress: {GuardDepsClosed},
+			TaskStatusDone:       {GuardDepsClosed},
+		},
+	}
+}
+
+var (
+	workflowMu sync.RWMutex
+	workflow   = DefaultWorkflow()
+)
+
+// SetWorkflow replaces the workflow used by IsReady, IsClosed and
+// transition checks
+func SetWorkflow(w *Workflow) {
+	workflowMu.Lock()
+	defer workflowMu.Unlock()
+	workflow = w
+}
+
+// CurrentWorkflow returns the active workflow
+func CurrentWorkflow() *Workflow {
+	workflowMu.RLock()
+	defer workflowMu.RUnlock()
+	return workflow
+}
+
+// IsClosed reports whether status is closed in the active workflow
+func IsClosed(status TaskStatus) bool {
+	return CurrentWorkflow().IsClosed(status)
+}
+
+// IsValid reports whether status belongs to the workflow
+func (w *Workflow) IsValid(status TaskStatus) bool {
+	return containsStatus(w.Statuses, status)
+}
+
+// IsClosed reports whether status no longer blocks dependents
+func (w *Workflow) IsClosed(status TaskStatus) bool {
+	return containsStatus(w.Closed, status)
+}
+
+// CanTransition reports whether a task may move directly from one status
+// to another
+func (w *Workflow) CanTransition(from, to TaskStatus) bool {
+	if from == to {
+		return true
+	}
+	allowed, ok := w.Transitions[from]
+	if !ok {
+		return true
+	}
+	return containsStatus(allowed, to)
+}
+
+// CheckTransition returns an error explaining why task cannot move to
+// status, or nil if it can
+func (w *Workflow) CheckTransition(task *Task, to TaskStatus, allTasks map[string]*Task) error {
+	if !w.IsValid(to) {
+		return fmt.Errorf("invalid status '%s'. Valid: %s", to, w.StatusList())
+	}
+
+	if !w.CanTransition(task.Status, to) {
+		allowed := joinStatuses(w.Transitions[task.Status])
+		if allowed == "" {
+			allowed = "none"
+		}
+		return fmt.Errorf("cannot move %s from %s to %s. Allowed: %s", task.ID, task.Status, to, allowed)
+	}
+
+	if task.Status == to {
+		return nil
+	}
+
+	for _, guard := range w.Guards[to] {
+		switch guard {
+		case GuardDepsClosed:
+			for _, depID := range task.DependsOn {
+				dep, ok := allTasks[depID]
+				if ok && !w.IsClosed(dep.Status) {
+					return fmt.Errorf("cannot move %s to %s: dependency %s is still %s", task.ID, to, dep.ID, dep.Status)
+				}
+			}
+
+		case GuardChildrenClosed:
+			for _, child := range allTasks {
+				if child.Parent == task.ID && !w.IsClosed(child.Status) {
+					return fmt.Errorf("cannot move %s to %s: child %s is still %s", task.ID, to, child.ID, child.Status)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// CanClaim returns an error if the workflow has no status for claimed work
+func (w *Workflow) CanClaim() error {
+	if w.Working == "" {
+		return fmt.Errorf("the workflow has no working status for claimed tasks; set workflow.working in config.yaml")
+	}
+	return nil
+}
+
+// StatusList returns the valid statuses as a comma-separated string
+func (w *Workflow) StatusList() string {
+	return joinStatuses(w.Statuses)
+}
+
+// Validate checks that the workflow is internally consistent
+func (w *Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return fmt.Errorf("workflow has no statuses")
+	}
+
+	seen := make(map[TaskStatus]bool)
+	for _, s := range w.Statuses {
+		if s == "" {
+			return fmt.Errorf("workflow has an empty status")
+		}
+		if seen[s] {
+			return fmt.Errorf("status '%s' is listed twice", s)
+		}
+		seen[s] = true
+	}
+
+	if !w.IsValid(w.Initial) {
+		return fmt.Errorf("initial status '%s' is not a workflow status", w.Initial)
+	}
+	if !w.IsValid(w.Done) {
+		return fmt.Errorf("done status '%s' is not a workflow status", w.Done)
+	}
+	if !w.IsClosed(w.Done) {
+		return fmt.Errorf("done status '%s' must be closed", w.Done)
+	}
+	for _, s := range w.Closed {
+		if !w.IsValid(s) {
+			return fmt.Errorf("closed status '%s' is not a workflow status", s)
+		}
+	}
+	for _, role := range []struct {
+		name   string
+		status TaskStatus
+	}{{"ready", w.Ready}, {"working", w.Working}} {
+		if role.status == "" {
+			continue
+		}
+		if !w.IsValid(role.status) {
+			return fmt.Errorf("%s status '%s' is not a workflow status", role.name, role.status)
+		}
+		if w.IsClosed(role.status) {
+			return fmt.Errorf("%s status '%s' must not be closed", role.name, role.status)
+		}
+	}
+	if w.AutoReady && w.Ready == "" {
+		return fmt.Errorf("auto_ready needs a ready status; set workflow.ready")
+	}
+
+	for from, tos := range w.Transitions {
+		if !w.IsValid(from) {
+			return fmt.Errorf("transition from unknown status '%s'", from)
+		}
+		for _, to := range tos {
+			if !w.IsValid(to) {
+				return fmt.Errorf("transition from '%s' to unknown status '%s'", from, to)
+			}
+		}
+	}
+
+	for status, guards := range w.Guards {
+		if !w.IsValid(status) {
+			return fmt.Errorf("guard on unknown status '%s'", status)
+		}
+		for _, g := range guards {
+			if !containsGuard(Guards, g) {
+				return fmt.Errorf("unknown guard '%s' on status '%s'", g, status)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsStatus(list []TaskStatus, status TaskStatus) bool {
+	for _, s := range list {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func containsGuard(list []Guard, guard Guard) bool {
+	for _, g := range list {
+		if g == guard {
+			return true
+		}
+	}
+	return false
+}
+
+func joinStatuses(list []TaskStatus) string {
+	names := make([]string, len(list))
+	for i, s := range list {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// guardedWorkflow is a small custom workflow with restricted transitions
+// and both guards
+func guardedWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []TaskStatus{"todo", "doing", "review", "done", "dropped"},
+		Initial:  "todo",
+		Done:     "done",
+		Closed:   []TaskStatus{"done", "dropped"},
+		Ready:    "todo",
+		Working:  "doing",
+		Transitions: map[TaskStatus][]TaskStatus{
+			"todo":   {"doing", "dropped"},
+			"doing":  {"review", "todo"},
+			"review": {"done", "doing"},
+		},
+		Guards: map[TaskStatus][]Guard{
+			"doing": {GuardDepsClosed},
+			"done":  {GuardDepsClosed, GuardChildrenClosed},
+		},
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	w := guardedWorkflow()
+	tasks := map[string]*Task{
+		"open-dep":    {ID: "open-dep", Status: "doing"},
+		"closed-dep":  {ID: "closed-dep", Status: "done"},
+		"dropped-dep": {ID: "dropped-dep", Status: "dropped"},
+		"open-child":  {ID: "open-child", Status: "todo", Parent: "epic"},
+		"done-child":  {ID: "done-child", Status: "done", Parent: "epic-done"},
+	}
+
+	tests := []struct {
+		name string
+		task *Task
+		to   TaskStatus
+		want string // Substring of the error, "" for allowed
+	}{
+		{name: "unknown status", task: &Task{ID: "t", Status: "todo"}, to: "shipped", want: "invalid status 'shipped'"},
+		{name: "allowed", task: &Task{ID: "t", Status: "todo"}, to: "doing"},
+		{name: "not allowed", task: &Task{ID: "t", Status: "todo"}, to: "done", want: "cannot move t from todo to done. Allowed: doing, dropped"},
+		{name: "no entry moves anywhere", task: &Task{ID: "t", Status: "done"}, to: "todo"},
+		{name: "same status", task: &Task{ID: "t", Status: "doing", DependsOn: []string{"open-dep"}}, to: "doing"},
+		{name: "open dependency", task: &Task{ID: "t", Status: "todo", DependsOn: []string{"open-dep"}}, to: "doing", want: "dependency open-dep is still doing"},
+		{name: "closed dependencies", task: &Task{ID: "t", Status: "todo", DependsOn: []string{"closed-dep", "dropped-dep"}}, to: "doing"},
+		{name: "missing dependency", task: &Task{ID: "t", Status: "todo", DependsOn: []string{"gone"}}, to: "doing"},
+		{name: "unguarded status", task: &Task{ID: "t", Status: "doing", DependsOn: []string{"open-dep"}}, to: "review"},
+		{name: "open child", task: &Task{ID: "epic", Status: "review"}, to: "done", want: "child open-child is still todo"},
+		{name: "closed children", task: &Task{ID: "epic-done", Status: "review"}, to: "done"},
+		{name: "dropping skips guards", task: &Task{ID: "epic", Status: "todo", DependsOn: []string{"open-dep"}}, to: "dropped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := w.CheckTransition(tt.task, tt.to, tasks)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("CheckTransition(%s → %s) = %v, want allowed", tt.task.Status, tt.to, err)
+			case tt.want != "" && err == nil:
+				t.Errorf("CheckTransition(%s → %s) allowed, want %q", tt.task.Status, tt.to, tt.want)
+			case tt.want != "" && !strings.Contains(err.Error(), tt.want):
+				t.Errorf("CheckTransition(%s → %s) = %q, want it to contain %q", tt.task.Status, tt.to, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultWorkflowGuards(t *testing.T) {
+	w := DefaultWorkflow()
+	tasks := map[string]*Task{
+		"dep": {ID: "dep", Status: TaskStatusReady},
+	}
+	task := &Task{ID: "t", Status: TaskStatusReady, DependsOn: []string{"dep"}}
+
+	tests := []struct {
+		to      TaskStatus
+		allowed bool
+	}{
+		{to: TaskStatusBacklog, allowed: true},
+		{to: TaskStatusBlocked, allowed: true},
+		{to: TaskStatusCancelled, allowed: true},
+		{to: TaskStatusInProgress, allowed: false},
+		{to: TaskStatusDone, allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.to), func(t *testing.T) {
+			err := w.CheckTransition(task, tt.to, tasks)
+			if (err == nil) != tt.allowed {
+				t.Errorf("CheckTransition(ready → %s) = %v, want allowed %v", tt.to, err, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(w *Workflow)
+		want   string
+	}{
+		{name: "valid", change: func(*Workflow) {}},
+		{name: "no statuses", change: func(w *Workflow) { w.Statuses = nil }, want: "no statuses"},
+		{name: "duplicate", change: func(w *Workflow) { w.Statuses = append(w.Statuses, "todo") }, want: "listed twice"},
+		{name: "unknown initial", change: func(w *Workflow) { w.Initial = "new" }, want: "initial status 'new'"},
+		{name: "open done", change: func(w *Workflow) { w.Closed = []TaskStatus{"dropped"} }, want: "must be closed"},
+		{name: "closed working", change: func(w *Workflow) { w.Working = "dropped" }, want: "working status 'dropped' must not be closed"},
+		{name: "auto_ready without ready", change: func(w *Workflow) { w.Ready = ""; w.AutoReady = true }, want: "auto_ready needs a ready status"},
+		{name: "bad transition", change: func(w *Workflow) { w.Transitions["todo"] = []TaskStatus{"shipped"} }, want: "unknown status 'shipped'"},
+		{name: "unknown guard", change: func(w *Workflow) { w.Guards["review"] = []Guard{"signed-off"} }, want: "unknown guard 'signed-off'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := guardedWorkflow()
+			tt.change(w)
+			err := w.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Validate = %v, want nil", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Validate = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package dates
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Layout is how calendar days are written in task files and accepted on
+// the command line
+const Layout = "2006-01-02"
+
+// weekdays maps full and short day names to weekdays
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// Parse resolves a natural day to the start of that local calendar day:
+//
+//	today, tomorrow, yesterday
+//	friday, fri           the next Friday, today included
+//	next friday           the Friday after that
+//	+3d, +2w, -1d         days or weeks from today
+//	2026-11-01            a date
+func Parse(value string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	today := Day(now)
+
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	next := false
+	if rest, ok := strings.CutPrefix(s, "next "); ok {
+		s, next = strings.TrimSpace(rest), true
+	}
+	if wd, ok := weekdays[s]; ok {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		if next {
+			days += 7
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+	if next {
+		return time.Time{}, invalid(value)
+	}
+
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		days, err := parseDays(s[1:])
+		if err != nil {
+			return time.Time{}, invalid(value)
+		}
+		if s[0] == '-' {
+			days = -days
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if t, err := time.ParseInLocation(Layout, s, time.Local); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, invalid(value)
+}
+
+// Day returns the start of t's local calendar day
+func Day(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// Format writes a day in Layout
+func Format(t time.Time) string {
+	return t.Local().Format(Layout)
+}
+
+// DaysBetween returns the number of calendar days from a to b
+func DaysBetween(a, b time.Time) int {
+	a, b = Day(a), Day(b)
+	// Round to absorb DST shifts
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+// ParseAge parses a relative age such as 30m, 12h, 7d or 2w
+func ParseAge(s string) (time.Duration, error) {
+	if len(s) < 2 || s[0] < '0' || s[0] > '9' {
+		return 0, fmt.Errorf("invalid age: %s", s)
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid age: %s", s)
+	}
+
+	switch unicode.ToLower(rune(s[len(s)-1])) {
+	case 'm':
+		return time.Duration(n) * time.Minute, nil
+	case 'h':
+		return time.Duration(n) * time.Hour, nil
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, nil
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("invalid age: %s", s)
+	}
+}
+
+// parseDays parses a day offset such as 3d or 2w
+func parseDays(s string) (int, error) {
+	if len(s) < 2 || s[0] < '0' || s[0] > '9' {
+		return 0, fmt.Errorf("invalid offset: %s", s)
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid offset: %s", s)
+	}
+
+	switch s[len(s)-1] {
+	case 'd':
+		return n, nil
+	case 'w':
+		return n * 7, nil
+	default:
+		return 0, fmt.Errorf("invalid offset: %s", s)
+	}
+}
+
+func invalid(value string) error {
+	return fmt.Errorf("invalid date '%s': use today, tomorrow, a weekday (friday), an offset (+3d, +2w) or a date (2006-01-02)", value)
+}
@@ -0,0 +1,32 @@
+package dates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses an effort such as 1h30m, 45m or 1.5h
+func ParseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration '%s': use e.g. 1h30m, 45m or 1.5h", s)
+	}
+	return d, nil
+}
+
+// FormatDuration renders a duration to the minute, e.g. 1h30m, 45m or 0m
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := int(d / time.Hour)
+	m := int((d % time.Hour) / time.Minute)
+
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+}
@@ -0,0 +1,265 @@
+// Package git runs the git command line for strand's git integration:
+// committing task changes, reading a task's commit history and finding
+// uncommitted task files.
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotRepository is returned when a directory is not inside a git work
+// tree
+var ErrNotRepository = errors.New("not a git repository")
+
+// Repo is a git work tree
+type Repo struct {
+	Root string // Top-level directory
+}
+
+// Commit is one commit touching a file
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body,omitempty"`
+}
+
+// FileStatus is an uncommitted change to a file
+type FileStatus struct {
+	Path   string `json:"path"`   // Relative to the repository root
+	Status string `json:"status"` // added, modified, deleted, renamed or untracked
+	Staged bool   `json:"staged"`
+}
+
+// Open finds the repository containing dir
+func Open(dir string) (*Repo, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git not found: %w", err)
+	}
+
+	out, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotRepository, dir)
+	}
+	return &Repo{Root: strings.TrimSpace(out)}, nil
+}
+
+// CommitPaths stages paths, including deletions, and commits only them,
+// leaving anything else the user has staged alone. It returns false if
+// none of the paths had changes.
+func (r *Repo) CommitPaths(message string, paths ...string) (bool, error) {
+	changed, err := r.Status(paths...)
+	if err != nil {
+		return false, err
+	}
+	if len(changed) == 0 {
+		return false, nil
+	}
+
+	var files []string
+	for _, f := range changed {
+		files = append(files, f.Path)
+	}
+
+	args := append([]string{"add", "--all", "--"}, files...)
+	if _, err := run(r.Root, args...); err != nil {
+		return false, fmt.Errorf("failed to stage task files: %w", err)
+	}
+	args = append([]string{"commit", "--quiet", "-m", message, "--"}, files...)
+	if _, err := run(r.Root, args...); err != nil {
+		return false, fmt.Errorf("failed to commit task files: %w", err)
+	}
+	return true, nil
+}
+
+// Status returns the uncommitted changes under paths
+func (r *Repo) Status(paths ...string) ([]FileStatus, error) {
+	args := append([]string{"status", "--porcelain=v1", "-z", "--untracked-files=all", "--"}, paths...)
+	out, err := run(r.Root, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git status: %w", err)
+	}
+
+	var files []FileStatus
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		x, y, path := entry[0], entry[1], entry[3:]
+		if x == 'R' || x == 'C' {
+			i++ // The original path follows
+		}
+
+		f := FileStatus{Path: path, Staged: x != ' ' && x != '?'}
+		code := x
+		if code == ' ' {
+			code = y
+		}
+		switch code {
+		case 'A':
+			f.Status = "added"
+		case 'D':
+			f.Status = "deleted"
+		case 'R', 'C':
+			f.Status = "renamed"
+		case '?':
+			f.Status = "untracked"
+		default:
+			f.Status = "modified"
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// Log returns the commits that changed path, newest first, following
+// renames
+func (r *Repo) Log(path string, limit int) ([]Commit, error) {
+	args := []string{"log", "--follow", "--format=%H%x1f%an%x1f%aI%x1f%s%x1f%b%x1e"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", limit))
+	}
+	args = append(args, "--", path)
+
+	out, err := run(r.Root, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		t, _ := time.Parse(time.RFC3339, fields[2])
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Time:    t,
+			Subject: fields[3],
+			Body:    strings.TrimSpace(fields[4]),
+		})
+	}
+	return commits, nil
+}
+
+// Show returns the change a commit made to path, as a patch
+func (r *Repo) Show(hash, path string) (string, error) {
+	out, err := run(r.Root, "show", "--format=", hash, "--", path)
+	if err != nil {
+		return "", fmt.Errorf("failed to show %s: %w", hash, err)
+	}
+	return out, nil
+}
+
+// SetConfig sets a key in the repository's own git config
+func (r *Repo) SetConfig(key, value string) error {
+	if _, err := run(r.Root, "config", "--local", key, value); err != nil {
+		return fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	return nil
+}
+
+// MergeFile merges the changes from base to other into current, in place,
+// as git merge-file does. It reports whether conflicts were left marked
+// in current. labels name current, base and other in the markers.
+func MergeFile(current, base, other string, labels ...string) (conflicts bool, err error) {
+	args := []string{"merge-file"}
+	for _, label := range labels {
+		args = append(args, "-L", label)
+	}
+	args = append(args, current, base, other)
+
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+
+	// The exit status is the number of conflicts, or negative on error
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("git merge-file: %s", strings.TrimSpace(stderr.String()+" "+err.Error()))
+	}
+	return false, nil
+}
+
+// MergeText merges the changes from base to theirs into ours, leaving
+// conflict markers where both changed the same lines
+func MergeText(base, ours, theirs string) (merged string, conflicts bool, err error) {
+	dir, err := os.MkdirTemp("", "strand-merge-")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(dir)
+
+	paths := make([]string, 3)
+	for i, text := range []string{ours, base, theirs} {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("%d", i))
+		if err := os.WriteFile(paths[i], []byte(text), 0644); err != nil {
+			return "", false, err
+		}
+	}
+
+	conflicts, err = MergeFile(paths[0], paths[1], paths[2], "ours", "base", "theirs")
+	if err != nil {
+		return "", false, err
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), conflicts, nil
+}
+
+// Rel returns path relative to the repository root, as git expects it
+func (r *Repo) Rel(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	// Resolve symlinks on both sides, e.g. /tmp on macOS
+	if resolved, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(resolved, filepath.Base(abs))
+	}
+	root := r.Root
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside the repository %s", path, r.Root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// run runs git in dir and returns its output
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
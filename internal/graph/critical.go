@@ -0,0 +1,295 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// DefaultEffort is the effort assumed for a task without an estimate
+const DefaultEffort = time.Hour
+
+// ScheduledTask is an unfinished task placed on the schedule. Times are
+// offsets from now, assuming unlimited hands.
+type ScheduledTask struct {
+	ID             string          `json:"id"`
+	Title          string          `json:"title"`
+	Status         core.TaskStatus `json:"status"`
+	Effort         core.Duration   `json:"effort"`
+	EarliestStart  core.Duration   `json:"earliest_start"`
+	EarliestFinish core.Duration   `json:"earliest_finish"`
+	LatestStart    core.Duration   `json:"latest_start"`
+	LatestFinish   core.Duration   `json:"latest_finish"`
+	Slack          core.Duration   `json:"slack"`
+	Critical       bool            `json:"critical"`
+	Blocks         int             `json:"blocks"` // Unfinished tasks waiting on this one
+
+	deps []string
+}
+
+// Schedule is the result of a critical path analysis
+type Schedule struct {
+	Target    string           `json:"target,omitempty"`
+	Length    core.Duration    `json:"length"`    // Effort along the critical path
+	Remaining core.Duration    `json:"remaining"` // Effort of all unfinished work
+	Path      []string         `json:"critical_path"`
+	Blockers  []string         `json:"blockers"` // Critical tasks that can start now, most blocking first
+	Tasks     []*ScheduledTask `json:"tasks"`
+}
+
+// Effort returns the work left on a task: its estimate minus the time
+// already logged, or DefaultEffort if it has no estimate
+func Effort(task *core.Task) time.Duration {
+	if task.Estimate == 0 {
+		return DefaultEffort
+	}
+	remaining := time.Duration(task.Estimate) - task.Logged()
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// CriticalPath schedules the unfinished work needed to finish target, or
+// every open task if target is "". A task waits for its dependencies and,
+// like an epic, for its children; a task with children has no effort of its
+// own. Returns an error naming the loop if the work contains a cycle.
+func CriticalPath(taskMap map[string]*core.Task, target string) (*Schedule, error) {
+	nodes := make(map[string]*ScheduledTask)
+
+	// Unfinished prerequisites of a task: dependencies and children
+	prereqs := func(task *core.Task) []string {
+		var ids []string
+		for _, depID := range task.DependsOn {
+			if dep, ok := taskMap[depID]; ok && !core.IsClosed(dep.Status) {
+				ids = append(ids, depID)
+			}
+		}
+		for _, child := range Children(taskMap, task.ID) {
+			if !core.IsClosed(child.Status) {
+				ids = append(ids, child.ID)
+			}
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	add := func(task *core.Task) *ScheduledTask {
+		node := &ScheduledTask{
+			ID:     task.ID,
+			Title:  task.Title,
+			Status: task.Status,
+			deps:   prereqs(task),
+		}
+		if len(Children(taskMap, task.ID)) == 0 {
+			node.Effort = core.Duration(Effort(task))
+		}
+		nodes[task.ID] = node
+		return node
+	}
+
+	if target != "" {
+		root, ok := taskMap[target]
+		if !ok {
+			return nil, fmt.Errorf("task not found: %s", target)
+		}
+		var collect func(task *core.Task)
+		collect = func(task *core.Task) {
+			if _, seen := nodes[task.ID]; seen || core.IsClosed(task.Status) {
+				return
+			}
+			for _, id := range add(task).deps {
+				collect(taskMap[id])
+			}
+		}
+		collect(root)
+	} else {
+		for _, id := range sortedIDs(taskMap) {
+			if task := taskMap[id]; !core.IsClosed(task.Status) {
+				add(task)
+			}
+		}
+	}
+
+	order, err := scheduleOrder(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &Schedule{Target: target, Path: []string{}, Blockers: []string{}, Tasks: []*ScheduledTask{}}
+
+	// Forward pass: earliest times
+	for _, node := range order {
+		for _, id := range node.deps {
+			if dep := nodes[id]; dep.EarliestFinish > node.EarliestStart {
+				node.EarliestStart = dep.EarliestFinish
+			}
+		}
+		node.EarliestFinish = node.EarliestStart + node.Effort
+		if node.EarliestFinish > schedule.Length {
+			schedule.Length = node.EarliestFinish
+		}
+		schedule.Remaining += node.Effort
+	}
+
+	// Backward pass: latest times
+	for _, node := range order {
+		node.LatestFinish = schedule.Length
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		node.LatestStart = node.LatestFinish - node.Effort
+		for _, id := range node.deps {
+			if dep := nodes[id]; node.LatestStart < dep.LatestFinish {
+				dep.LatestFinish = node.LatestStart
+			}
+		}
+	}
+
+	for _, node := range order {
+		node.Slack = node.LatestStart - node.EarliestStart
+		node.Critical = node.Slack == 0
+		node.Blocks = countWaiting(nodes, node.ID)
+		schedule.Tasks = append(schedule.Tasks, node)
+	}
+
+	sort.SliceStable(schedule.Tasks, func(i, j int) bool {
+		a, b := schedule.Tasks[i], schedule.Tasks[j]
+		if a.EarliestStart != b.EarliestStart {
+			return a.EarliestStart < b.EarliestStart
+		}
+		if a.Slack != b.Slack {
+			return a.Slack < b.Slack
+		}
+		return a.ID < b.ID
+	})
+
+	schedule.Path = criticalChain(nodes, schedule)
+
+	var blockers []*ScheduledTask
+	for _, node := range schedule.Tasks {
+		if node.Critical && len(node.deps) == 0 {
+			blockers = append(blockers, node)
+		}
+	}
+	sort.SliceStable(blockers, func(i, j int) bool {
+		return blockers[i].Blocks > blockers[j].Blocks
+	})
+	for _, node := range blockers {
+		schedule.Blockers = append(schedule.Blockers, node.ID)
+	}
+
+	return schedule, nil
+}
+
+// scheduleOrder sorts nodes so every task comes after its prerequisites
+func scheduleOrder(nodes map[string]*ScheduledTask) ([]*ScheduledTask, error) {
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var order []*ScheduledTask
+	var stack []string
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			for i, s := range stack {
+				if s == id {
+					return fmt.Errorf("cannot schedule a dependency cycle: %s", FormatPath(append(stack[i:], id)))
+				}
+			}
+		}
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, dep := range nodes[id].deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+		order = append(order, nodes[id])
+		return nil
+	}
+
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// criticalChain walks back from the target, or the last task to finish
+// that nothing waits on, through prerequisites with no slack, returning the
+// path in working order
+func criticalChain(nodes map[string]*ScheduledTask, schedule *Schedule) []string {
+	var end *ScheduledTask
+	if node, ok := nodes[schedule.Target]; ok {
+		end = node
+	} else {
+		for _, node := range schedule.Tasks {
+			if node.Critical && node.EarliestFinish == schedule.Length && node.Blocks == 0 {
+				end = node
+				break
+			}
+		}
+	}
+	if end == nil {
+		return []string{}
+	}
+
+	path := []string{end.ID}
+	for node := end; ; {
+		var next *ScheduledTask
+		for _, id := range node.deps {
+			dep := nodes[id]
+			if dep.Critical && dep.EarliestFinish == node.EarliestStart {
+				next = dep
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		path = append([]string{next.ID}, path...)
+		node = next
+	}
+	return path
+}
+
+// countWaiting counts the scheduled tasks that wait on id, directly or
+// through other tasks
+func countWaiting(nodes map[string]*ScheduledTask, id string) int {
+	waiting := make(map[string]bool)
+	var walk func(id string)
+	walk = func(id string) {
+		for _, node := range nodes {
+			if waiting[node.ID] {
+				continue
+			}
+			for _, dep := range node.deps {
+				if dep == id {
+					waiting[node.ID] = true
+					walk(node.ID)
+					break
+				}
+			}
+		}
+	}
+	walk(id)
+	return len(waiting)
+}
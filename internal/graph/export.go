@@ -0,0 +1,299 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// Filter selects part of the dependency graph for export
+type Filter struct {
+	Root     string            // Only this task and the tasks that depend on it
+	Statuses []core.TaskStatus // Only tasks in one of these statuses
+	Tags     []string          // Only tasks with at least one of these tags
+	Depth    int               // Only tasks at most this many edges from a root; 0 is unlimited
+}
+
+// Edge is a dependency: From must finish before To
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DAG is a selection of tasks and the dependencies between them
+type DAG struct {
+	Nodes []*core.Task
+	Edges []Edge
+
+	// Effective holds each node's effective status, blocked while it
+	// waits on an open dependency, even one filtered out of the graph
+	Effective map[string]core.TaskStatus
+}
+
+// Node is a task as exported in JSON, without its description
+type Node struct {
+	ID       string            `json:"id"`
+	Title    string            `json:"title"`
+	Type     core.TaskType     `json:"type"`
+	Status   core.TaskStatus   `json:"status"`
+	Priority core.TaskPriority `json:"priority,omitempty"`
+	// Effective is blocked while the task waits on an open dependency
+	Effective core.TaskStatus `json:"effective_status"`
+	Assignee  string          `json:"assignee,omitempty"`
+	Tags      []string        `json:"tags,omitempty"`
+	Parent    string          `json:"parent,omitempty"`
+}
+
+// MarshalJSON writes the graph as {"nodes": [...], "edges": [...]}
+func (d *DAG) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Nodes []Node `json:"nodes"`
+		Edges []Edge `json:"edges"`
+	}{Nodes: []Node{}, Edges: d.Edges}
+	for _, t := range d.Nodes {
+		out.Nodes = append(out.Nodes, Node{
+			ID:        t.ID,
+			Title:     t.Title,
+			Type:      t.Type,
+			Status:    t.Status,
+			Priority:  t.Priority,
+			Effective: d.Effective[t.ID],
+			Assignee:  t.Assignee,
+			Tags:      t.Tags,
+			Parent:    t.Parent,
+		})
+	}
+	return json.Marshal(out)
+}
+
+// Select returns the tasks that pass the filter, sorted by ID, with the
+// dependencies between them
+func Select(taskMap map[string]*core.Task, filter Filter) (*DAG, error) {
+	dependents := Dependents(taskMap)
+
+	// Distance from a root, following dependents
+	depth := make(map[string]int)
+	var queue []string
+	if filter.Root != "" {
+		if _, ok := taskMap[filter.Root]; !ok {
+			return nil, fmt.Errorf("task not found: %s", filter.Root)
+		}
+		queue = []string{filter.Root}
+		depth[filter.Root] = 0
+	} else {
+		for _, id := range sortedIDs(taskMap) {
+			if len(knownDeps(taskMap, taskMap[id])) == 0 {
+				queue = append(queue, id)
+				depth[id] = 0
+			}
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range dependents[id] {
+			if _, seen := depth[next]; !seen {
+				depth[next] = depth[id] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	keep := make(map[string]bool)
+	dag := &DAG{Edges: []Edge{}, Effective: make(map[string]core.TaskStatus)}
+	for _, id := range sortedIDs(taskMap) {
+		task := taskMap[id]
+		d, reached := depth[id]
+		if filter.Root != "" && !reached {
+			continue
+		}
+		// Tasks stuck in a cycle are never reached from a root
+		if filter.Depth > 0 && (!reached || d > filter.Depth) {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !hasStatus(task, filter.Statuses) {
+			continue
+		}
+		if len(filter.Tags) > 0 && !hasAnyTag(task, filter.Tags) {
+			continue
+		}
+		keep[id] = true
+		dag.Nodes = append(dag.Nodes, task)
+		dag.Effective[id] = task.EffectiveStatus(taskMap)
+	}
+
+	for _, task := range dag.Nodes {
+		for _, depID := range knownDeps(taskMap, task) {
+			if keep[depID] {
+				dag.Edges = append(dag.Edges, Edge{From: depID, To: task.ID})
+			}
+		}
+	}
+
+	return dag, nil
+}
+
+// Dependents maps each task ID to the IDs of the tasks that depend on it,
+// sorted
+func Dependents(taskMap map[string]*core.Task) map[string][]string {
+	dependents := make(map[string][]string)
+	for _, id := range sortedIDs(taskMap) {
+		for _, depID := range taskMap[id].DependsOn {
+			dependents[depID] = append(dependents[depID], id)
+		}
+	}
+	return dependents
+}
+
+// knownDeps returns a task's dependencies that exist, sorted
+func knownDeps(taskMap map[string]*core.Task, task *core.Task) []string {
+	var deps []string
+	for _, depID := range task.DependsOn {
+		if _, ok := taskMap[depID]; ok {
+			deps = append(deps, depID)
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+func hasStatus(task *core.Task, statuses []core.TaskStatus) bool {
+	for _, s := range statuses {
+		if task.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyTag(task *core.Task, tags []string) bool {
+	for _, want := range tags {
+		for _, tag := range task.Tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// statusColors are the fill colors for DOT and Mermaid nodes
+var statusColors = map[core.TaskStatus]string{
+	core.TaskStatusBacklog:    "#eeeeee",
+	core.TaskStatusReady:      "#c8e6c9",
+	core.TaskStatusInProgress: "#bbdefb",
+	core.TaskStatusBlocked:    "#ffcdd2",
+	core.TaskStatusDone:       "#a5d6a7",
+	core.TaskStatusCancelled:  "#cfd8dc",
+}
+
+// priorityBorders are the border colors for DOT and Mermaid nodes
+var priorityBorders = map[core.TaskPriority]string{
+	core.TaskPriorityCritical: "#d32f2f",
+	core.TaskPriorityHigh:     "#f57c00",
+	core.TaskPriorityMedium:   "#616161",
+	core.TaskPriorityLow:      "#9e9e9e",
+}
+
+func statusColor(status core.TaskStatus) string {
+	if c, ok := statusColors[status]; ok {
+		return c
+	}
+	return "#ffffff"
+}
+
+func priorityBorder(priority core.TaskPriority) string {
+	if c, ok := priorityBorders[priority]; ok {
+		return c
+	}
+	return "#616161"
+}
+
+// WriteDOT renders the graph for Graphviz, e.g. dot -Tsvg
+func WriteDOT(w io.Writer, dag *DAG) error {
+	var b strings.Builder
+	b.WriteString("digraph strand {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+
+	for _, task := range dag.Nodes {
+		status := dag.Effective[task.ID]
+		penwidth := 1
+		if task.Priority == core.TaskPriorityCritical || task.Priority == core.TaskPriorityHigh {
+			penwidth = 2
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%q, color=%q, penwidth=%d];\n",
+			dotQuote(task.ID),
+			dotQuote(task.ID+"\n"+task.Title+"\n["+string(status)+"]"),
+			statusColor(status),
+			priorityBorder(task.Priority),
+			penwidth,
+		)
+	}
+	for _, e := range dag.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart, ready to paste
+// into a ```mermaid block
+func WriteMermaid(w io.Writer, dag *DAG) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	for _, task := range dag.Nodes {
+		fmt.Fprintf(&b, "  %s[\"%s<br/>%s\"]\n",
+			mermaidID(task.ID),
+			mermaidEscape(task.Title),
+			mermaidEscape(string(dag.Effective[task.ID])),
+		)
+	}
+	for _, e := range dag.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", mermaidID(e.From), mermaidID(e.To))
+	}
+	for _, task := range dag.Nodes {
+		fmt.Fprintf(&b, "  style %s fill:%s,stroke:%s\n",
+			mermaidID(task.ID),
+			statusColor(dag.Effective[task.ID]),
+			priorityBorder(task.Priority),
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote quotes a DOT ID or label
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidID turns a task ID into a Mermaid node ID, which may not contain
+// dashes
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// mermaidEscape escapes text inside a quoted Mermaid label
+func mermaidEscape(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "<", "#lt;")
+	s = strings.ReplaceAll(s, ">", "#gt;")
+	return s
+}
@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// TaskMap indexes tasks by ID
+func TaskMap(tasks []*core.Task) map[string]*core.Task {
+	taskMap := make(map[string]*core.Task, len(tasks))
+	for _, t := range tasks {
+		taskMap[t.ID] = t
+	}
+	return taskMap
+}
+
+// sortedIDs returns the map keys in a stable order so results are deterministic
+func sortedIDs(taskMap map[string]*core.Task) []string {
+	ids := make([]string, 0, len(taskMap))
+	for id := range taskMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// FindPath returns a dependency path from -> ... -> to following depends_on
+// edges, or nil if to is not reachable from from
+func FindPath(taskMap map[string]*core.Task, from, to string) []string {
+	visited := make(map[string]bool)
+
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if id == to {
+			return []string{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+
+		task, ok := taskMap[id]
+		if !ok {
+			return nil
+		}
+		for _, depID := range task.DependsOn {
+			if path := walk(depID); path != nil {
+				return append([]string{id}, path...)
+			}
+		}
+		return nil
+	}
+
+	return walk(from)
+}
+
+// WouldCycle reports the cycle that making taskID depend on dependsOnID
+// would create, as a path starting and ending with taskID, or nil if none
+func WouldCycle(taskMap map[string]*core.Task, taskID, dependsOnID string) []string {
+	if taskID == dependsOnID {
+		return []string{taskID, taskID}
+	}
+
+	path := FindPath(taskMap, dependsOnID, taskID)
+	if path == nil {
+		return nil
+	}
+	return append([]string{taskID}, path...)
+}
+
+// FindCycles returns every dependency cycle reachable by a depth-first
+// search, one per back edge. Each cycle starts and ends with the same ID.
+func FindCycles(taskMap map[string]*core.Task) [][]string {
+	const (
+		unvisited = iota
+		inStack
+		done
+	)
+
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+	seen := make(map[string]bool)
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = inStack
+		stack = append(stack, id)
+
+		task := taskMap[id]
+		deps := append([]string(nil), task.DependsOn...)
+		sort.Strings(deps)
+
+		for _, depID := range deps {
+			if _, ok := taskMap[depID]; !ok {
+				continue // Dangling reference, reported separately
+			}
+
+			switch state[depID] {
+			case unvisited:
+				visit(depID)
+			case inStack:
+				// Back edge: the cycle is the stack from depID to here
+				start := 0
+				for i, sid := range stack {
+					if sid == depID {
+						start = i
+						break
+					}
+				}
+				cycle := append(append([]string(nil), stack[start:]...), depID)
+
+				key := cycleKey(cycle)
+				if !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+
+	for _, id := range sortedIDs(taskMap) {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+
+	return cycles
+}
+
+// cycleKey normalizes a cycle's rotation so duplicates can be detected
+func cycleKey(cycle []string) string {
+	nodes := cycle[:len(cycle)-1]
+
+	min := 0
+	for i, id := range nodes {
+		if id < nodes[min] {
+			min = i
+		}
+	}
+
+	rotated := append(append([]string(nil), nodes[min:]...), nodes[:min]...)
+	return strings.Join(rotated, "\x00")
+}
+
+// DanglingRef is a depends_on entry pointing at a task that does not exist
+type DanglingRef struct {
+	TaskID    string `json:"task_id"`
+	DependsOn string `json:"depends_on"`
+}
+
+// FindDangling returns every depends_on reference to a missing task
+func FindDangling(taskMap map[string]*core.Task) []DanglingRef {
+	var refs []DanglingRef
+	for _, id := range sortedIDs(taskMap) {
+		for _, depID := range taskMap[id].DependsOn {
+			if _, ok := taskMap[depID]; !ok {
+				refs = append(refs, DanglingRef{TaskID: id, DependsOn: depID})
+			}
+		}
+	}
+	return refs
+}
+
+// FormatPath renders a path of task IDs as "a → b → c"
+func FormatPath(path []string) string {
+	return strings.Join(path, " → ")
+}
@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// deps builds a task map from "id:dep,dep" specs
+func deps(specs ...string) map[string]*core.Task {
+	taskMap := make(map[string]*core.Task)
+	for _, spec := range specs {
+		id, list, _ := strings.Cut(spec, ":")
+		task := &core.Task{ID: id}
+		if list != "" {
+			task.DependsOn = strings.Split(list, ",")
+		}
+		taskMap[id] = task
+	}
+	return taskMap
+}
+
+// parents builds a task map from "id:parent" specs
+func parents(specs ...string) map[string]*core.Task {
+	taskMap := make(map[string]*core.Task)
+	for _, spec := range specs {
+		id, parent, _ := strings.Cut(spec, ":")
+		taskMap[id] = &core.Task{ID: id, Parent: parent}
+	}
+	return taskMap
+}
+
+func TestWouldCycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		taskMap   map[string]*core.Task
+		task, dep string
+		want      []string
+	}{
+		{name: "self", taskMap: deps("a"), task: "a", dep: "a", want: []string{"a", "a"}},
+		{name: "independent", taskMap: deps("a", "b"), task: "a", dep: "b"},
+		{name: "already downstream", taskMap: deps("a:b", "b:c", "c"), task: "a", dep: "c"},
+		{name: "direct", taskMap: deps("a", "b:a"), task: "a", dep: "b", want: []string{"a", "b", "a"}},
+		{name: "transitive", taskMap: deps("a", "b:a", "c:b"), task: "a", dep: "c", want: []string{"a", "c", "b", "a"}},
+		{name: "diamond", taskMap: deps("a", "b:a", "c:a", "d:b,c"), task: "a", dep: "d", want: []string{"a", "d", "b", "a"}},
+		{name: "dangling", taskMap: deps("a", "b:missing"), task: "a", dep: "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WouldCycle(tt.taskMap, tt.task, tt.dep)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WouldCycle(%s, %s) = %v, want %v", tt.task, tt.dep, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		taskMap map[string]*core.Task
+		want    [][]string
+	}{
+		{name: "none", taskMap: deps("a:b", "b:c", "c")},
+		{name: "self", taskMap: deps("a:a"), want: [][]string{{"a", "a"}}},
+		{name: "pair", taskMap: deps("a:b", "b:a"), want: [][]string{{"a", "b", "a"}}},
+		{name: "triangle", taskMap: deps("a:b", "b:c", "c:a"), want: [][]string{{"a", "b", "c", "a"}}},
+		{name: "two loops", taskMap: deps("a:b", "b:a", "c:d", "d:c"), want: [][]string{{"a", "b", "a"}, {"c", "d", "c"}}},
+		{name: "dangling ignored", taskMap: deps("a:missing", "b")},
+		{name: "tail into loop", taskMap: deps("a:b", "b:c", "c:b"), want: [][]string{{"b", "c", "b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindCycles(tt.taskMap)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindCycles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParentCycle(t *testing.T) {
+	tests := []struct {
+		name         string
+		taskMap      map[string]*core.Task
+		task, parent string
+		want         []string
+	}{
+		{name: "self", taskMap: parents("a"), task: "a", parent: "a", want: []string{"a", "a"}},
+		{name: "unrelated", taskMap: parents("a", "b"), task: "a", parent: "b"},
+		{name: "move under sibling", taskMap: parents("e", "a:e", "b:e"), task: "a", parent: "b"},
+		{name: "child", taskMap: parents("a", "b:a"), task: "a", parent: "b", want: []string{"a", "b", "a"}},
+		{name: "grandchild", taskMap: parents("a", "b:a", "c:b"), task: "a", parent: "c", want: []string{"a", "c", "b", "a"}},
+		{name: "missing parent", taskMap: parents("a"), task: "a", parent: "missing"},
+		{name: "existing loop above", taskMap: parents("a", "b:c", "c:b"), task: "a", parent: "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParentCycle(tt.taskMap, tt.task, tt.parent)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParentCycle(%s, %s) = %v, want %v", tt.task, tt.parent, got, tt.want)
+			}
+		})
+	}
+}
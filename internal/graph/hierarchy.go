@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// Children returns the direct children of a task, sorted by ID
+func Children(taskMap map[string]*core.Task, id string) []*core.Task {
+	var children []*core.Task
+	for _, t := range taskMap {
+		if t.Parent == id {
+			children = append(children, t)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].ID < children[j].ID
+	})
+	return children
+}
+
+// Descendants returns every task below id in the hierarchy, depth-first
+func Descendants(taskMap map[string]*core.Task, id string) []*core.Task {
+	var result []*core.Task
+	visited := map[string]bool{id: true}
+
+	var walk func(parentID string)
+	walk = func(parentID string) {
+		for _, child := range Children(taskMap, parentID) {
+			if visited[child.ID] {
+				continue // Guard against hand-edited hierarchy loops
+			}
+			visited[child.ID] = true
+			result = append(result, child)
+			walk(child.ID)
+		}
+	}
+	walk(id)
+
+	return result
+}
+
+// Ancestors returns the parent chain of a task, nearest first
+func Ancestors(taskMap map[string]*core.Task, id string) []*core.Task {
+	var result []*core.Task
+	visited := map[string]bool{id: true}
+
+	task, ok := taskMap[id]
+	for ok && task.Parent != "" && !visited[task.Parent] {
+		visited[task.Parent] = true
+		task, ok = taskMap[task.Parent]
+		if ok {
+			result = append(result, task)
+		}
+	}
+
+	return result
+}
+
+// ParentCycle reports the hierarchy loop that giving taskID the parent
+// parentID would create, as a path starting and ending with taskID, or nil
+func ParentCycle(taskMap map[string]*core.Task, taskID, parentID string) []string {
+	path := []string{taskID}
+	visited := make(map[string]bool)
+
+	for id := parentID; id != "" && !visited[id]; {
+		visited[id] = true
+		path = append(path, id)
+		if id == taskID {
+			return path
+		}
+
+		task, ok := taskMap[id]
+		if !ok {
+			break
+		}
+		id = task.Parent
+	}
+
+	return nil
+}
+
+// Rollup summarizes the progress of everything below a task
+type Rollup struct {
+	Total    int                     `json:"total"`
+	Done     int                     `json:"done"`
+	Percent  int                     `json:"percent"` // Done out of those not otherwise closed
+	ByStatus map[core.TaskStatus]int `json:"by_status"`
+	Blocked  []string                `json:"blocked,omitempty"` // Open descendants waiting on dependencies
+}
+
+// ComputeRollup summarizes all descendants of id. It returns nil if the
+// task has no children.
+func ComputeRollup(taskMap map[string]*core.Task, id string) *Rollup {
+	descendants := Descendants(taskMap, id)
+	if len(descendants) == 0 {
+		return nil
+	}
+
+	workflow := core.CurrentWorkflow()
+
+	r := &Rollup{ByStatus: make(map[core.TaskStatus]int)}
+	dropped := 0
+	for _, t := range descendants {
+		r.Total++
+		r.ByStatus[t.Status]++
+
+		switch {
+		case t.Status == workflow.Done:
+			r.Done++
+		case workflow.IsClosed(t.Status):
+			dropped++ // Cancelled and other closed statuses
+		case t.Status == core.TaskStatusBlocked || !t.IsReady(taskMap):
+			r.Blocked = append(r.Blocked, t.ID)
+		}
+	}
+
+	if counted := r.Total - dropped; counted > 0 {
+		r.Percent = r.Done * 100 / counted
+	} else {
+		r.Percent = 100
+	}
+
+	return r
+}
+
+// AllChildrenClosed reports whether a task has children and every one of
+// them is closed
+func AllChildrenClosed(taskMap map[string]*core.Task, id string) bool {
+	children := Children(taskMap, id)
+	if len(children) == 0 {
+		return false
+	}
+	for _, child := range children {
+		if !core.IsClosed(child.Status) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// Impact describes the work waiting on a task
+type Impact struct {
+	Blocks   int      `json:"blocks"`   // Open tasks that depend on it, directly or through other tasks
+	Unblocks []string `json:"unblocks"` // Open tasks that become ready once it is closed
+}
+
+// DirectDependents returns the tasks that list id in depends_on, sorted by ID
+func DirectDependents(taskMap map[string]*core.Task, id string) []*core.Task {
+	var result []*core.Task
+	for _, depID := range Dependents(taskMap)[id] {
+		result = append(result, taskMap[depID])
+	}
+	return result
+}
+
+// TransitiveDependents returns every task that depends on id, directly or
+// through other tasks, sorted by ID
+func TransitiveDependents(taskMap map[string]*core.Task, id string) []*core.Task {
+	dependents := Dependents(taskMap)
+	seen := map[string]bool{id: true}
+	var result []*core.Task
+
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range dependents[current] {
+			if !seen[next] {
+				seen[next] = true
+				result = append(result, taskMap[next])
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// ComputeImpact counts the open work waiting on a task. A dependent is
+// unblocked by it when every other dependency is already closed.
+func ComputeImpact(taskMap map[string]*core.Task, id string) Impact {
+	impact := Impact{Unblocks: []string{}}
+
+	for _, task := range TransitiveDependents(taskMap, id) {
+		if !core.IsClosed(task.Status) {
+			impact.Blocks++
+		}
+	}
+
+	for _, task := range DirectDependents(taskMap, id) {
+		if core.IsClosed(task.Status) {
+			continue
+		}
+		waiting := false
+		for _, depID := range task.DependsOn {
+			if dep, ok := taskMap[depID]; ok && depID != id && !core.IsClosed(dep.Status) {
+				waiting = true
+				break
+			}
+		}
+		if !waiting {
+			impact.Unblocks = append(impact.Unblocks, task.ID)
+		}
+	}
+
+	return impact
+}
@@ -0,0 +1,310 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+)
+
+// FileName is the append-only event log inside .strand/
+const FileName = "history.jsonl"
+
+// Actions recorded in the log
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionDelete    = "delete"
+	ActionEdit      = "edit"       // Changed in $EDITOR
+	ActionClaim     = "claim"      // Claimed by an agent
+	ActionRelease   = "release"    // Claim released or expired
+	ActionHeartbeat = "heartbeat"  // Lease renewed
+	ActionAutoClose = "auto-close" // Closed when its last child closed
+	ActionAutoReady = "auto-ready" // Made ready when its last dependency closed
+	ActionComment   = "comment"
+	ActionTime      = "time" // Timer started or stopped, or time logged
+)
+
+// Event is a single recorded change to a task. Updates produce one event
+// per changed field; for list fields Old holds the removed items and New
+// the added ones.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	TaskID string    `json:"task_id"`
+	Action string    `json:"action"`
+	Field  string    `json:"field,omitempty"`
+	Old    string    `json:"old,omitempty"`
+	New    string    `json:"new,omitempty"`
+}
+
+// Log is the project's history file
+type Log struct {
+	path string
+}
+
+// Open returns the history log for a .strand directory. The file is
+// created on first append.
+func Open(strandDir string) *Log {
+	return &Log{path: filepath.Join(strandDir, FileName)}
+}
+
+// Append writes events to the end of the log in a single write
+func (l *Log) Append(events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for _, e := range events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+
+	return nil
+}
+
+// Read returns the events accepted by keep, oldest first. Lines that fail
+// to parse, such as a partial line from a crash, are skipped.
+func (l *Log) Read(keep func(Event) bool) ([]Event, error) {
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if keep == nil || keep(e) {
+			events = append(events, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	return events, nil
+}
+
+// Created returns the event for a new task
+func Created(actor string, task *core.Task) Event {
+	return Event{
+		Time:   task.Created,
+		Actor:  actor,
+		TaskID: task.ID,
+		Action: ActionCreate,
+		New:    task.Title,
+	}
+}
+
+// Deleted returns the event for a removed task
+func Deleted(actor string, task *core.Task, now time.Time) Event {
+	return Event{
+		Time:   now,
+		Actor:  actor,
+		TaskID: task.ID,
+		Action: ActionDelete,
+		Old:    task.Title,
+	}
+}
+
+// Commented returns the event for a new comment
+func Commented(actor string, task *core.Task, c core.Comment) Event {
+	return Event{
+		Time:   c.Created,
+		Actor:  actor,
+		TaskID: task.ID,
+		Action: ActionComment,
+		New:    c.Body,
+	}
+}
+
+// Renewed returns the event for a lease renewed from before to after
+func Renewed(actor string, before, after *core.Task, now time.Time) Event {
+	return Event{
+		Time:   now,
+		Actor:  actor,
+		TaskID: after.ID,
+		Action: ActionHeartbeat,
+		Field:  "lease_expires",
+		Old:    formatTime(before.LeaseExpires),
+		New:    formatTime(after.LeaseExpires),
+	}
+}
+
+// Diff returns an event for every field that differs between before and
+// after, tagged with action. Leases are left out: claims show up as
+// assignee and status changes, and renewals as Renewed events.
+func Diff(actor, action string, before, after *core.Task, now time.Time) []Event {
+	var events []Event
+	add := func(field, old, new string) {
+		if old == new {
+			return
+		}
+		events = append(events, Event{
+			Time:   now,
+			Actor:  actor,
+			TaskID: after.ID,
+			Action: action,
+			Field:  field,
+			Old:    old,
+			New:    new,
+		})
+	}
+	addList := func(field string, old, new []string) {
+		removed, added := listDiff(old, new)
+		add(field, strings.Join(removed, ", "), strings.Join(added, ", "))
+	}
+
+	add("title", before.Title, after.Title)
+	add("type", string(before.Type), string(after.Type))
+	add("status", string(before.Status), string(after.Status))
+	add("priority", string(before.Priority), string(after.Priority))
+	add("assignee", before.Assignee, after.Assignee)
+	add("parent", before.Parent, after.Parent)
+	add("auto_close", formatBool(before.AutoClose), formatBool(after.AutoClose))
+	add("due", formatDay(before.Due), formatDay(after.Due))
+	add("start", formatDay(before.Start), formatDay(after.Start))
+	add("estimate", formatDuration(time.Duration(before.Estimate)), formatDuration(time.Duration(after.Estimate)))
+	add("logged", formatDuration(before.Logged()), formatDuration(after.Logged()))
+	add("timer", strings.Join(before.RunningTimers(), ", "), strings.Join(after.RunningTimers(), ", "))
+	add("description", before.Description, after.Description)
+	addList("tags", before.Tags, after.Tags)
+	addList("depends_on", before.DependsOn, after.DependsOn)
+
+	return events
+}
+
+// Snapshot copies a task so later edits do not change the copy
+func Snapshot(task *core.Task) *core.Task {
+	snapshot := *task
+	snapshot.Tags = append([]string(nil), task.Tags...)
+	snapshot.DependsOn = append([]string(nil), task.DependsOn...)
+	snapshot.TimeLog = append([]core.TimeEntry(nil), task.TimeLog...)
+	return &snapshot
+}
+
+// String describes the event on one line
+func (e Event) String() string {
+	switch {
+	case e.Action == ActionCreate:
+		return fmt.Sprintf("created %q", e.New)
+	case e.Action == ActionDelete:
+		return fmt.Sprintf("deleted %q", e.Old)
+	case e.Action == ActionComment:
+		return fmt.Sprintf("commented %q", truncate(e.New, 60))
+	case e.Field == "description":
+		return "description changed"
+	case e.Field == "tags" || e.Field == "depends_on":
+		var parts []string
+		if e.New != "" {
+			parts = append(parts, "+"+e.New)
+		}
+		if e.Old != "" {
+			parts = append(parts, "-"+e.Old)
+		}
+		return fmt.Sprintf("%s: %s", e.Field, strings.Join(parts, " "))
+	default:
+		return fmt.Sprintf("%s: %s → %s", e.Field, orNone(e.Old), orNone(e.New))
+	}
+}
+
+// listDiff returns the items only in old and only in new, sorted
+func listDiff(old, new []string) (removed, added []string) {
+	inOld := make(map[string]bool)
+	for _, s := range old {
+		inOld[s] = true
+	}
+	inNew := make(map[string]bool)
+	for _, s := range new {
+		inNew[s] = true
+		if !inOld[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range old {
+		if !inNew[s] {
+			removed = append(removed, s)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+	return removed, added
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "true"
+	}
+	return ""
+}
+
+// truncate shortens s to the first line and at most n characters
+func truncate(s string, n int) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + "…"
+	}
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func formatDay(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return dates.Format(*t)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return dates.FormatDuration(d)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
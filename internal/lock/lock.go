@@ -0,0 +1,60 @@
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrTimeout is returned when the lock could not be acquired in time
+var ErrTimeout = errors.New("timed out waiting for lock")
+
+// pollInterval is how often Acquire retries a busy lock
+const pollInterval = 50 * time.Millisecond
+
+// Lock is an advisory, process-wide lock backed by a file
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes an exclusive advisory lock on path, creating the file if
+// needed. It retries until timeout elapses; a zero timeout tries once.
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if ok {
+			return &Lock{file: f}, nil
+		}
+
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w: %s is held by another strand process", ErrTimeout, path)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Release releases the lock. It is safe to call on a nil Lock.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	err := unlock(l.file)
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	l.file = nil
+	return err
+}
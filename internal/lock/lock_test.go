@@ -0,0 +1,87 @@
+package lock
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquire(t *testing.T) {
+	tests := []struct {
+		name    string
+		held    bool
+		timeout time.Duration
+		wantErr error
+	}{
+		{name: "free", timeout: 0},
+		{name: "free with timeout", timeout: time.Second},
+		{name: "held, try once", held: true, timeout: 0, wantErr: ErrTimeout},
+		{name: "held, wait", held: true, timeout: 3 * pollInterval, wantErr: ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "lock")
+			if tt.held {
+				holder, err := Acquire(path, 0)
+				if err != nil {
+					t.Fatalf("first Acquire: %v", err)
+				}
+				defer holder.Release()
+			}
+
+			l, err := Acquire(path, tt.timeout)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Acquire = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Acquire: %v", err)
+			}
+			if err := l.Release(); err != nil {
+				t.Errorf("Release: %v", err)
+			}
+		})
+	}
+}
+
+func TestAcquireAfterRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock")
+
+	first, err := Acquire(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The waiter gets the lock once the holder lets go
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(2 * pollInterval)
+		first.Release()
+		close(released)
+	}()
+
+	second, err := Acquire(path, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Acquire while waiting for release: %v", err)
+	}
+	<-released
+	if err := second.Release(); err != nil {
+		t.Errorf("Release: %v", err)
+	}
+}
+
+func TestReleaseTwice(t *testing.T) {
+	l, err := Acquire(filepath.Join(t.TempDir(), "lock"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []*Lock{l, l, nil} {
+		if err := l.Release(); err != nil {
+			t.Errorf("Release: %v", err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package markdown
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// contentHash returns the version string stored in core.Task.Version
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeTemp writes data to a new temporary file next to filename and
+// returns its path. The .tmp suffix keeps it out of List and git.
+func writeTemp(filename string, data []byte) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmpName := f.Name()
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmpName)
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpName)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpName)
+		return "", err
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		os.Remove(tmpName)
+		return "", err
+	}
+
+	return tmpName, nil
+}
+
+// writeFileAtomic replaces filename with data so that readers only ever see
+// the old or the new content, never a truncated file
+func writeFileAtomic(filename string, data []byte) error {
+	tmpName, err := writeTemp(filename, data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpName, filename); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	return nil
+}
+
+// createFileAtomic writes data to filename only if it does not exist yet
+func createFileAtomic(filename string, data []byte) error {
+	tmpName, err := writeTemp(filename, data)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpName)
+
+	// A hard link fails if the target exists, giving an atomic no-clobber create
+	if err := os.Link(tmpName, filename); err != nil {
+		if os.IsExist(err) {
+			return err
+		}
+
+		// Filesystems without hard links: fall back to check-then-rename,
+		// which is still safe while the project lock is held
+		if _, statErr := os.Stat(filename); statErr == nil {
+			return fmt.Errorf("%s: %w", filename, os.ErrExist)
+		}
+		return os.Rename(tmpName, filename)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // "" for no file
+		data     string
+	}{
+		{name: "new file", data: "new"},
+		{name: "replace", existing: "old content", data: "new"},
+		{name: "empty", existing: "old", data: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "task.md")
+			if tt.existing != "" {
+				if err := os.WriteFile(filename, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := writeFileAtomic(filename, []byte(tt.data)); err != nil {
+				t.Fatalf("writeFileAtomic: %v", err)
+			}
+			if got := readFile(t, filename); got != tt.data {
+				t.Errorf("content = %q, want %q", got, tt.data)
+			}
+			checkNoTemp(t, dir)
+		})
+	}
+}
+
+func TestCreateFileAtomic(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   string
+		data       string
+		wantExists bool
+	}{
+		{name: "new file", data: "new"},
+		{name: "never clobbers", existing: "old", data: "new", wantExists: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "task.md")
+			if tt.existing != "" {
+				if err := os.WriteFile(filename, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := createFileAtomic(filename, []byte(tt.data))
+			if tt.wantExists {
+				if !os.IsExist(err) {
+					t.Fatalf("createFileAtomic = %v, want an exists error", err)
+				}
+				if got := readFile(t, filename); got != tt.existing {
+					t.Errorf("content = %q, want the original %q", got, tt.existing)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("createFileAtomic: %v", err)
+				}
+				if got := readFile(t, filename); got != tt.data {
+					t.Errorf("content = %q, want %q", got, tt.data)
+				}
+			}
+			checkNoTemp(t, dir)
+		})
+	}
+}
+
+func readFile(t *testing.T, filename string) string {
+	t.Helper()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// checkNoTemp fails if a temporary file was left behind in dir
+func checkNoTemp(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("temporary file left behind: %s", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package markdown
+
+import (
+	"strings"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// commentsHeading starts the comments section at the end of a task file.
+// Each comment follows as
+//
+//	### author — 2026-01-17T14:20:00Z
+//
+//	Comment body...
+const commentsHeading = "## Comments"
+
+// commentSeparator sits between a comment's author and timestamp
+const commentSeparator = " — "
+
+// formatComments renders the comments section, or "" if there are none
+func formatComments(comments []core.Comment) string {
+	if len(comments) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(commentsHeading)
+	b.WriteString("\n")
+	for _, c := range comments {
+		b.WriteString("\n### ")
+		b.WriteString(c.Author)
+		b.WriteString(commentSeparator)
+		b.WriteString(c.Created.Format("2006-01-02T15:04:05Z07:00"))
+		b.WriteString("\n\n")
+		for _, line := range strings.Split(c.Body, "\n") {
+			b.WriteString(escapeMarkup(line))
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// splitComments separates the comments section from the rest of the body.
+// It starts at the first "## Comments" heading followed directly by a
+// comment header, so a description that happens to use the same heading is
+// kept.
+func splitComments(body string) (string, []core.Comment) {
+	lines := strings.Split(body, "\n")
+
+	start := -1
+	for i := 0; i < len(lines) && start < 0; i++ {
+		if strings.TrimSpace(lines[i]) != commentsHeading {
+			continue
+		}
+		for _, next := range lines[i+1:] {
+			if strings.TrimSpace(next) == "" {
+				continue
+			}
+			if _, ok := parseCommentHeader(next); ok {
+				start = i
+			}
+			break
+		}
+	}
+	if start < 0 {
+		return body, nil
+	}
+
+	var comments []core.Comment
+	var current *core.Comment
+	var text []string
+
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimSpace(strings.Join(text, "\n"))
+			comments = append(comments, *current)
+		}
+		text = nil
+	}
+
+	for _, line := range lines[start+1:] {
+		if c, ok := parseCommentHeader(line); ok {
+			flush()
+			current = &c
+			continue
+		}
+		if current == nil {
+			continue // Blank lines before the first header
+		}
+		text = append(text, unescapeMarkup(line))
+	}
+	flush()
+
+	return strings.TrimSpace(strings.Join(lines[:start], "\n")), comments
+}
+
+// parseCommentHeader parses "### author — timestamp"
+func parseCommentHeader(line string) (core.Comment, bool) {
+	if !strings.HasPrefix(line, "### ") {
+		return core.Comment{}, false
+	}
+
+	header := strings.TrimPrefix(line, "### ")
+	i := strings.LastIndex(header, commentSeparator)
+	if i < 0 {
+		return core.Comment{}, false
+	}
+
+	created, err := parseTime(strings.TrimSpace(header[i+len(commentSeparator):]))
+	if err != nil {
+		return core.Comment{}, false
+	}
+
+	return core.Comment{
+		Author:  strings.TrimSpace(header[:i]),
+		Created: created,
+	}, true
+}
+
+// escapeMarkup backslash-escapes a comment body line that would otherwise
+// read as the comments heading or a comment header
+func escapeMarkup(line string) string {
+	if isMarkup(strings.TrimLeft(line, `\`)) {
+		return `\` + line
+	}
+	return line
+}
+
+// unescapeMarkup undoes escapeMarkup
+func unescapeMarkup(line string) string {
+	if strings.HasPrefix(line, `\`) && isMarkup(strings.TrimLeft(line, `\`)) {
+		return line[1:]
+	}
+	return line
+}
+
+// isMarkup reports whether line is the comments heading or a comment header
+func isMarkup(line string) bool {
+	if strings.TrimSpace(line) == commentsHeading {
+		return true
+	}
+	_, ok := parseCommentHeader(line)
+	return ok
+}
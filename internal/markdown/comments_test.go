@@ -0,0 +1,81 @@
+package markdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+func TestEscapeMarkup(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "plain text", want: "plain text"},
+		{line: "## Comments", want: `\## Comments`},
+		{line: "  ## Comments  ", want: `\  ## Comments  `},
+		{line: "### alice — 2026-01-17T14:20:00Z", want: `\### alice — 2026-01-17T14:20:00Z`},
+		{line: `\## Comments`, want: `\\## Comments`},
+		{line: "### A heading", want: "### A heading"},
+		{line: "### alice — yesterday", want: "### alice — yesterday"},
+		{line: `\not markup`, want: `\not markup`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := escapeMarkup(tt.line)
+			if got != tt.want {
+				t.Errorf("escapeMarkup(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+			if back := unescapeMarkup(got); back != tt.line {
+				t.Errorf("unescapeMarkup(%q) = %q, want %q", got, back, tt.line)
+			}
+		})
+	}
+}
+
+func TestCommentsRoundTrip(t *testing.T) {
+	created := time.Date(2026, 1, 17, 14, 20, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		description string
+		bodies      []string
+	}{
+		{name: "single", bodies: []string{"Looks good"}},
+		{name: "several", bodies: []string{"First", "Second\n\nwith a paragraph"}},
+		{name: "body with comments heading", bodies: []string{"Quoting:\n## Comments\nend"}},
+		{name: "body with comment header", bodies: []string{"### bob — 2026-01-18T09:00:00Z\nfake", "real"}},
+		{name: "body with escaped markup", bodies: []string{`\## Comments`, `\\### bob — 2026-01-18T09:00:00Z`}},
+		{name: "description with the heading", description: "Notes\n\n## Comments\n\nnot a comment", bodies: []string{"real"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comments []core.Comment
+			for _, body := range tt.bodies {
+				comments = append(comments, core.Comment{Author: "alice", Created: created, Body: body})
+			}
+
+			body := formatComments(comments)
+			if tt.description != "" {
+				body = tt.description + "\n\n" + body
+			}
+
+			description, got := splitComments(body)
+			if description != tt.description {
+				t.Errorf("description = %q, want %q", description, tt.description)
+			}
+			if len(got) != len(comments) {
+				t.Fatalf("got %d comments, want %d:\n%s", len(got), len(comments), body)
+			}
+			for i, c := range got {
+				want := comments[i]
+				if c.Author != want.Author || !c.Created.Equal(want.Created) || c.Body != want.Body {
+					t.Errorf("comment %d = %+v, want %+v", i, c, want)
+				}
+			}
+		})
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/hamsa0x7/strand/internal/storage"
 	"gopkg.in/yaml.v3"
 )
 
@@ -39,31 +42,40 @@ func (s *Store) Create(task *core.Task) error {
 	filename := s.taskFilename(task.ID)
 	task.FilePath = filename
 
-	content, err := s.taskToMarkdown(task)
+	content, err := Format(task)
 	if err != nil {
 		return fmt.Errorf("failed to convert task to markdown: %w", err)
 	}
 
-	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+	// Never overwrite an existing task with a colliding ID
+	if err := createFileAtomic(filename, []byte(content)); err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("%w: %s", storage.ErrExists, task.ID)
+		}
 		return fmt.Errorf("failed to write task file: %w", err)
 	}
+	task.Version = contentHash([]byte(content))
 
 	return nil
 }
 
-// Get retrieves a task by ID
+// Get retrieves a task by ID or unique ID prefix
 func (s *Store) Get(id string) (*core.Task, error) {
-	filename := s.taskFilename(id)
+	fullID, err := s.Resolve(id)
+	if err != nil {
+		return nil, err
+	}
+	filename := s.taskFilename(fullID)
 
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("task not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", storage.ErrNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to read task file: %w", err)
 	}
 
-	task, err := s.markdownToTask(data, filename)
+	task, err := Parse(data, filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse task: %w", err)
 	}
@@ -93,7 +105,7 @@ func (s *Store) List() ([]*core.Task, error) {
 			continue // Skip unreadable files
 		}
 
-		task, err := s.markdownToTask(data, filename)
+		task, err := Parse(data, filename)
 		if err != nil {
 			continue // Skip unparseable files
 		}
@@ -104,19 +116,91 @@ func (s *Store) List() ([]*core.Task, error) {
 	return tasks, nil
 }
 
-// Update updates an existing task
+// Resolve expands an ID or unique ID prefix to a full task ID
+func (s *Store) Resolve(prefix string) (string, error) {
+	// IDs can come from remote clients; never let one name a file outside
+	// the tasks directory
+	if !safeID(prefix) {
+		return "", fmt.Errorf("%w: %s", storage.ErrNotFound, prefix)
+	}
+
+	// Exact match wins, even if it is also a prefix of other IDs
+	if _, err := os.Stat(s.taskFilename(prefix)); err == nil {
+		return prefix, nil
+	}
+
+	entries, err := os.ReadDir(s.tasksDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read tasks directory: %w", err)
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+
+		id := strings.TrimSuffix(entry.Name(), ".md")
+		if core.MatchesIDPrefix(id, prefix) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", storage.ErrNotFound, prefix)
+	case 1:
+		return candidates[0], nil
+	default:
+		sort.Strings(candidates)
+		return "", &storage.AmbiguousIDError{Prefix: prefix, Candidates: candidates}
+	}
+}
+
+// Update updates an existing task.
+//
+// If the task carries a Version (it was read from this store), the file on
+// disk must still match it; otherwise a *storage.ConflictError is returned
+// instead of overwriting the other writer's changes.
 func (s *Store) Update(task *core.Task) error {
-	// Same as Create - overwrite the file
-	return s.Create(task)
+	filename := s.taskFilename(task.ID)
+	task.FilePath = filename
+
+	current, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", storage.ErrNotFound, task.ID)
+		}
+		return fmt.Errorf("failed to read task file: %w", err)
+	}
+	if task.Version != "" && contentHash(current) != task.Version {
+		return &storage.ConflictError{ID: task.ID}
+	}
+
+	content, err := Format(task)
+	if err != nil {
+		return fmt.Errorf("failed to convert task to markdown: %w", err)
+	}
+
+	if err := writeFileAtomic(filename, []byte(content)); err != nil {
+		return fmt.Errorf("failed to write task file: %w", err)
+	}
+	task.Version = contentHash([]byte(content))
+
+	return nil
 }
 
 // Delete deletes a task
 func (s *Store) Delete(id string) error {
-	filename := s.taskFilename(id)
+	fullID, err := s.Resolve(id)
+	if err != nil {
+		return err
+	}
+	filename := s.taskFilename(fullID)
 
 	if err := os.Remove(filename); err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("task not found: %s", id)
+			return fmt.Errorf("%w: %s", storage.ErrNotFound, id)
 		}
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -147,18 +231,44 @@ func (s *Store) Ready() ([]*core.Task, error) {
 	return readyTasks, nil
 }
 
+// TasksDir returns the directory holding the task files
+func (s *Store) TasksDir() string {
+	return s.tasksDir
+}
+
+// Load reads and parses a single task file
+func (s *Store) Load(filename string) (*core.Task, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task file: %w", err)
+	}
+
+	task, err := Parse(data, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse task: %w", err)
+	}
+
+	return task, nil
+}
+
 // Close closes the store (no-op for markdown)
 func (s *Store) Close() error {
 	return nil
 }
 
 // taskFilename returns the full path to a task's markdown file
+// safeID reports whether id can only name a file directly inside the tasks
+// directory
+func safeID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, `/\`) && !strings.Contains(id, "..")
+}
+
 func (s *Store) taskFilename(id string) string {
 	return filepath.Join(s.tasksDir, id+".md")
 }
 
-// taskToMarkdown converts a task to markdown format
-func (s *Store) taskToMarkdown(task *core.Task) (string, error) {
+// Format renders a task as a markdown file
+func Format(task *core.Task) (string, error) {
 	var buf bytes.Buffer
 
 	// Write YAML frontmatter
@@ -175,6 +285,13 @@ func (s *Store) taskToMarkdown(task *core.Task) (string, error) {
 		Created   string            `yaml:"created"`
 		Updated   string            `yaml:"updated"`
 		Tags      []string          `yaml:"tags,omitempty"`
+		Lease     string            `yaml:"lease_expires,omitempty"`
+		Parent    string            `yaml:"parent,omitempty"`
+		AutoClose bool              `yaml:"auto_close,omitempty"`
+		Due       string            `yaml:"due,omitempty"`
+		Start     string            `yaml:"start,omitempty"`
+		Estimate  string            `yaml:"estimate,omitempty"`
+		TimeLog   []timeEntry       `yaml:"time_log,omitempty"`
 	}
 
 	fm := Frontmatter{
@@ -187,6 +304,21 @@ func (s *Store) taskToMarkdown(task *core.Task) (string, error) {
 		Created:   task.Created.Format("2006-01-02T15:04:05Z07:00"),
 		Updated:   task.Updated.Format("2006-01-02T15:04:05Z07:00"),
 		Tags:      task.Tags,
+		Parent:    task.Parent,
+		AutoClose: task.AutoClose,
+		TimeLog:   formatTimeLog(task.TimeLog),
+	}
+	if task.LeaseExpires != nil {
+		fm.Lease = task.LeaseExpires.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if task.Due != nil {
+		fm.Due = dates.Format(*task.Due)
+	}
+	if task.Start != nil {
+		fm.Start = dates.Format(*task.Start)
+	}
+	if task.Estimate > 0 {
+		fm.Estimate = dates.FormatDuration(time.Duration(task.Estimate))
 	}
 
 	encoder := yaml.NewEncoder(&buf)
@@ -209,11 +341,20 @@ func (s *Store) taskToMarkdown(task *core.Task) (string, error) {
 		buf.WriteString("\n")
 	}
 
+	// Write comments
+	if comments := formatComments(task.Comments); comments != "" {
+		if task.Description != "" {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(comments)
+	}
+
 	return buf.String(), nil
 }
 
-// markdownToTask parses a markdown file into a Task
-func (s *Store) markdownToTask(data []byte, filename string) (*core.Task, error) {
+// Parse parses the contents of a task file. filename is recorded as the
+// task's FilePath.
+func Parse(data []byte, filename string) (*core.Task, error) {
 	content := string(data)
 
 	// Split frontmatter and body
@@ -233,6 +374,13 @@ func (s *Store) markdownToTask(data []byte, filename string) (*core.Task, error)
 		Created   string            `yaml:"created"`
 		Updated   string            `yaml:"updated"`
 		Tags      []string          `yaml:"tags"`
+		Lease     string            `yaml:"lease_expires"`
+		Parent    string            `yaml:"parent"`
+		AutoClose bool              `yaml:"auto_close"`
+		Due       string            `yaml:"due"`
+		Start     string            `yaml:"start"`
+		Estimate  string            `yaml:"estimate"`
+		TimeLog   []timeEntry       `yaml:"time_log"`
 	}
 
 	var fm Frontmatter
@@ -246,12 +394,13 @@ func (s *Store) markdownToTask(data []byte, filename string) (*core.Task, error)
 
 	var title string
 	var description string
+	var comments []core.Comment
 
 	if len(lines) > 0 && strings.HasPrefix(lines[0], "# ") {
 		title = strings.TrimPrefix(lines[0], "# ")
 		if len(lines) > 1 {
 			description = strings.Join(lines[1:], "\n")
-			description = strings.TrimSpace(description)
+			description, comments = splitComments(strings.TrimSpace(description))
 		}
 	}
 
@@ -266,7 +415,12 @@ func (s *Store) markdownToTask(data []byte, filename string) (*core.Task, error)
 		DependsOn:   fm.DependsOn,
 		Assignee:    fm.Assignee,
 		Tags:        fm.Tags,
+		Parent:      fm.Parent,
+		AutoClose:   fm.AutoClose,
+		Comments:    comments,
+		TimeLog:     parseTimeLog(fm.TimeLog),
 		FilePath:    filename,
+		Version:     contentHash(data),
 	}
 
 	// Parse timestamps
@@ -276,10 +430,34 @@ func (s *Store) markdownToTask(data []byte, filename string) (*core.Task, error)
 	if updated, err := parseTime(fm.Updated); err == nil {
 		task.Updated = updated
 	}
+	if lease, err := parseTime(fm.Lease); err == nil {
+		task.LeaseExpires = &lease
+	}
+	if due, err := parseDay(fm.Due); err == nil {
+		task.Due = &due
+	}
+	if start, err := parseDay(fm.Start); err == nil {
+		task.Start = &start
+	}
+	if estimate, err := dates.ParseDuration(fm.Estimate); err == nil {
+		task.Estimate = core.Duration(estimate)
+	}
 
 	return task, nil
 }
 
+// parseDay parses a calendar day, also accepting a full timestamp
+func parseDay(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(dates.Layout, s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := parseTime(s)
+	if err != nil {
+		return t, err
+	}
+	return dates.Day(t), nil
+}
+
 // parseTime parses an ISO 8601 timestamp
 func parseTime(s string) (time.Time, error) {
 	// Try RFC3339 format first
@@ -0,0 +1,153 @@
+package markdown
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/storage"
+)
+
+// newTestStore returns a store in a temporary project holding tasks with
+// the given IDs
+func newTestStore(t *testing.T, ids ...string) *Store {
+	t.Helper()
+
+	store := NewStore()
+	if err := store.Init(filepath.Join(t.TempDir(), ".strand")); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		if err := store.Create(testTask(id)); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	return store
+}
+
+func testTask(id string) *core.Task {
+	now := time.Date(2026, 1, 17, 14, 20, 0, 0, time.UTC)
+	return &core.Task{
+		ID:       id,
+		Type:     core.TaskTypeTask,
+		Status:   core.TaskStatusBacklog,
+		Priority: core.TaskPriorityMedium,
+		Title:    "Task " + id,
+		Created:  now,
+		Updated:  now,
+	}
+}
+
+func TestResolve(t *testing.T) {
+	store := newTestStore(t, "strand-a3f9c1d2", "strand-a3f90000", "strand-b71c0a9e", "strand-c0")
+
+	tests := []struct {
+		name       string
+		prefix     string
+		want       string
+		wantErr    error
+		candidates []string
+	}{
+		{name: "full ID", prefix: "strand-b71c0a9e", want: "strand-b71c0a9e"},
+		{name: "prefix", prefix: "strand-b7", want: "strand-b71c0a9e"},
+		{name: "bare prefix", prefix: "b71c", want: "strand-b71c0a9e"},
+		{name: "longest unique", prefix: "a3f9c", want: "strand-a3f9c1d2"},
+		{name: "exact beats prefix", prefix: "strand-c0", want: "strand-c0"},
+		{name: "ambiguous", prefix: "a3f9", candidates: []string{"strand-a3f90000", "strand-a3f9c1d2"}},
+		{name: "ambiguous with strand-", prefix: "strand-a3", candidates: []string{"strand-a3f90000", "strand-a3f9c1d2"}},
+		{name: "no match", prefix: "ffff", wantErr: storage.ErrNotFound},
+		{name: "empty", prefix: "", wantErr: storage.ErrNotFound},
+		{name: "parent directory", prefix: "..", wantErr: storage.ErrNotFound},
+		{name: "traversal", prefix: "../config", wantErr: storage.ErrNotFound},
+		{name: "separator", prefix: "tasks/strand-b71c0a9e", wantErr: storage.ErrNotFound},
+		{name: "backslash", prefix: `..\strand-b71c0a9e`, wantErr: storage.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Resolve(tt.prefix)
+
+			var ambiguous *storage.AmbiguousIDError
+			switch {
+			case tt.candidates != nil:
+				if !errors.As(err, &ambiguous) {
+					t.Fatalf("Resolve(%q) = %q, %v, want an ambiguous ID error", tt.prefix, got, err)
+				}
+				if len(ambiguous.Candidates) != len(tt.candidates) {
+					t.Fatalf("candidates = %v, want %v", ambiguous.Candidates, tt.candidates)
+				}
+				for i, id := range tt.candidates {
+					if ambiguous.Candidates[i] != id {
+						t.Errorf("candidates = %v, want %v", ambiguous.Candidates, tt.candidates)
+						break
+					}
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve(%q) = %q, %v, want %v", tt.prefix, got, err, tt.wantErr)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Resolve(%q): %v", tt.prefix, err)
+				}
+				if got != tt.want {
+					t.Errorf("Resolve(%q) = %q, want %q", tt.prefix, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateExisting(t *testing.T) {
+	store := newTestStore(t, "strand-a3f9c1d2")
+
+	task := testTask("strand-a3f9c1d2")
+	task.Title = "Replacement"
+	if err := store.Create(task); !errors.Is(err, storage.ErrExists) {
+		t.Fatalf("Create over an existing task = %v, want ErrExists", err)
+	}
+
+	got, err := store.Get("strand-a3f9c1d2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Task strand-a3f9c1d2" {
+		t.Errorf("title = %q, the original task was overwritten", got.Title)
+	}
+}
+
+func TestUpdateConflict(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      func(read *core.Task) string
+		wantConflict bool
+	}{
+		{name: "current version", version: func(read *core.Task) string { return read.Version }},
+		{name: "no version", version: func(*core.Task) string { return "" }},
+		{name: "stale version", version: func(*core.Task) string { return "stale" }, wantConflict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t, "strand-a3f9c1d2")
+			task, err := store.Get("strand-a3f9c1d2")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			task.Version = tt.version(task)
+			task.Title = "Changed"
+			err = store.Update(task)
+
+			var conflict *storage.ConflictError
+			if got := errors.As(err, &conflict); got != tt.wantConflict {
+				t.Fatalf("Update = %v, want conflict %v", err, tt.wantConflict)
+			}
+			if !tt.wantConflict && err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package markdown
+
+import (
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// timeEntry is a work session as written in the frontmatter time_log list
+type timeEntry struct {
+	Agent string `yaml:"agent"`
+	Start string `yaml:"start"`
+	End   string `yaml:"end,omitempty"`
+	Note  string `yaml:"note,omitempty"`
+}
+
+// formatTimeLog converts work sessions to their frontmatter form
+func formatTimeLog(log []core.TimeEntry) []timeEntry {
+	var entries []timeEntry
+	for _, e := range log {
+		entry := timeEntry{
+			Agent: e.Agent,
+			Start: e.Start.Format("2006-01-02T15:04:05Z07:00"),
+			Note:  e.Note,
+		}
+		if e.End != nil {
+			entry.End = e.End.Format("2006-01-02T15:04:05Z07:00")
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseTimeLog reads work sessions from the frontmatter, skipping entries
+// without a valid start time
+func parseTimeLog(entries []timeEntry) []core.TimeEntry {
+	var log []core.TimeEntry
+	for _, entry := range entries {
+		start, err := parseTime(entry.Start)
+		if err != nil {
+			continue
+		}
+		e := core.TimeEntry{Agent: entry.Agent, Start: start, Note: entry.Note}
+		if end, err := parseTime(entry.End); err == nil {
+			e.End = &end
+		}
+		log = append(log, e)
+	}
+	return log
+}
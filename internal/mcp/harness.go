@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Harness drives a Server in-process over pipes, the way an MCP client
+// would over stdio. It is used by 'strand mcp check' and for exercising
+// the server without spawning a process.
+type Harness struct {
+	requests  *io.PipeWriter
+	responses *bufio.Reader
+	done      chan error
+	nextID    int
+}
+
+// NewHarness starts serving s in the background
+func NewHarness(s *Server) *Harness {
+	reqReader, reqWriter := io.Pipe()
+	respReader, respWriter := io.Pipe()
+
+	h := &Harness{
+		requests:  reqWriter,
+		responses: bufio.NewReader(respReader),
+		done:      make(chan error, 1),
+	}
+	go func() {
+		err := s.Serve(reqReader, respWriter)
+		respWriter.Close()
+		h.done <- err
+	}()
+	return h
+}
+
+// Call sends a request and decodes its result into result, which may be
+// nil. A JSON-RPC error is returned as an *Error.
+func (h *Harness) Call(method string, params, result interface{}) error {
+	h.nextID++
+	id, _ := json.Marshal(h.nextID)
+	if err := h.send(Request{JSONRPC: "2.0", ID: id, Method: method}, params); err != nil {
+		return err
+	}
+
+	line, err := h.responses.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if string(resp.ID) != string(id) {
+		return fmt.Errorf("response id %s does not match request id %s", resp.ID, id)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// Notify sends a notification, which gets no response
+func (h *Harness) Notify(method string, params interface{}) error {
+	return h.send(Request{JSONRPC: "2.0", Method: method}, params)
+}
+
+// Initialize performs the handshake and returns the negotiated protocol
+// version
+func (h *Harness) Initialize() (string, error) {
+	var result initializeResult
+	params := map[string]interface{}{
+		"protocolVersion": protocolVersions[0],
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": "strand-harness", "version": "1"},
+	}
+	if err := h.Call("initialize", params, &result); err != nil {
+		return "", err
+	}
+	if err := h.Notify("notifications/initialized", nil); err != nil {
+		return "", err
+	}
+	return result.ProtocolVersion, nil
+}
+
+// ListTools returns the tools the server offers
+func (h *Harness) ListTools() ([]Tool, error) {
+	var result struct {
+		Tools []Tool `json:"tools"`
+	}
+	err := h.Call("tools/list", nil, &result)
+	return result.Tools, err
+}
+
+// CallTool calls a tool with args and returns its result
+func (h *Harness) CallTool(name string, args interface{}) (*CallToolResult, error) {
+	var result CallToolResult
+	err := h.Call("tools/call", map[string]interface{}{"name": name, "arguments": args}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResources returns the resources the server offers
+func (h *Harness) ListResources() ([]Resource, error) {
+	var result struct {
+		Resources []Resource `json:"resources"`
+	}
+	err := h.Call("resources/list", nil, &result)
+	return result.Resources, err
+}
+
+// ReadResource returns the contents of a resource
+func (h *Harness) ReadResource(uri string) ([]ResourceContents, error) {
+	var result struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+	err := h.Call("resources/read", map[string]string{"uri": uri}, &result)
+	return result.Contents, err
+}
+
+// Close ends the session and returns the server's error, if any
+func (h *Harness) Close() error {
+	h.requests.Close()
+	return <-h.done
+}
+
+// send writes one message
+func (h *Harness) send(req Request, params interface{}) error {
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to encode params: %w", err)
+		}
+		req.Params = data
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	if _, err := h.requests.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	return nil
+}
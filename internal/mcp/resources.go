@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// taskURIPrefix starts the URI of every task file resource
+const taskURIPrefix = "strand://task/"
+
+// resourceTemplates describe the resources the server can read
+var resourceTemplates = []ResourceTemplate{
+	{
+		URITemplate: taskURIPrefix + "{id}",
+		Name:        "task",
+		Description: "A task's markdown file: YAML frontmatter, description and comments",
+		MimeType:    "text/markdown",
+	},
+}
+
+// listResources lists every task file
+func (s *Server) listResources() (interface{}, *Error) {
+	tasks, err := s.store.List()
+	if err != nil {
+		return nil, errorf(codeInternalError, "failed to list tasks: %v", err)
+	}
+
+	resources := []Resource{}
+	for _, task := range tasks {
+		resources = append(resources, Resource{
+			URI:         taskURIPrefix + task.ID,
+			Name:        task.ID,
+			Title:       task.Title,
+			Description: fmt.Sprintf("%s %s, %s", task.Priority, task.Type, task.Status),
+			MimeType:    "text/markdown",
+		})
+	}
+	return map[string]interface{}{"resources": resources}, nil
+}
+
+// readResource returns a task's markdown file as stored on disk
+func (s *Server) readResource(uri string) (interface{}, *Error) {
+	id := strings.TrimPrefix(uri, taskURIPrefix)
+	if id == uri || id == "" {
+		return nil, errorf(codeInvalidParams, "unknown resource: %s", uri)
+	}
+
+	task, err := s.store.Get(id)
+	if err != nil {
+		return nil, errorf(codeInvalidParams, "resource not found: %s: %v", uri, err)
+	}
+	data, err := os.ReadFile(task.FilePath)
+	if err != nil {
+		return nil, errorf(codeInternalError, "failed to read %s: %v", task.FilePath, err)
+	}
+
+	return map[string]interface{}{
+		"contents": []ResourceContents{{
+			URI:      taskURIPrefix + task.ID,
+			MimeType: "text/markdown",
+			Text:     string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// Schema is a JSON Schema, as much of it as tool descriptions need
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(core.Duration(0))
+	statusType        = reflect.TypeOf(core.TaskStatus(""))
+	priorityType      = reflect.TypeOf(core.TaskPriority(""))
+	taskTypeType      = reflect.TypeOf(core.TaskType(""))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SchemaFor derives a JSON Schema from the json tags of v's type. Fields
+// without omitempty are required, a desc tag becomes the description, and
+// task statuses, priorities and types become enums.
+func SchemaFor(v interface{}) *Schema {
+	return schemaOf(reflect.TypeOf(v))
+}
+
+func schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "string", Description: "Duration such as 1h30m"}
+	case statusType:
+		return &Schema{Type: "string", Enum: statusNames()}
+	case priorityType:
+		return &Schema{Type: "string", Enum: []string{
+			string(core.TaskPriorityCritical),
+			string(core.TaskPriorityHigh),
+			string(core.TaskPriorityMedium),
+			string(core.TaskPriorityLow),
+		}}
+	case taskTypeType:
+		return &Schema{Type: "string", Enum: []string{
+			string(core.TaskTypeTask),
+			string(core.TaskTypeEpic),
+			string(core.TaskTypeBug),
+			string(core.TaskTypeStory),
+		}}
+	}
+	if t.Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		addFields(s, t)
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// addFields adds a struct's fields to s, flattening embedded structs the
+// way encoding/json does
+func addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addFields(s, embedded)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := schemaOf(f.Type)
+		if desc := f.Tag.Get("desc"); desc != "" {
+			prop.Description = desc
+		}
+		s.Properties[name] = prop
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// statusNames lists the statuses of the project workflow
+func statusNames() []string {
+	statuses := core.CurrentWorkflow().Statuses
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = string(s)
+	}
+	return names
+}
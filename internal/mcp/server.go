@@ -0,0 +1,278 @@
+// Package mcp serves strand over the Model Context Protocol, so AI agents
+// can read and change tasks through structured tool calls instead of
+// parsing command output.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/hamsa0x7/strand/internal/service"
+	"github.com/hamsa0x7/strand/internal/storage"
+	"github.com/hamsa0x7/strand/internal/watch"
+)
+
+// maxMessageSize bounds a single JSON-RPC message
+const maxMessageSize = 16 << 20
+
+// Server answers MCP requests, running tools through a service.Service
+type Server struct {
+	store   storage.Store
+	svc     *service.Service
+	version string
+	tools   []*toolDef
+
+	mu sync.Mutex // One request at a time
+
+	outMu sync.Mutex // Guards out, shared by responses and notifications
+	out   *bufio.Writer
+
+	subMu      sync.Mutex
+	subscribed map[string]bool // Resource URIs the client asked to hear about
+}
+
+// NewServer creates a server whose tools run through svc and whose
+// resources are read from store. version is reported in serverInfo. The
+// project workflow must already be set, since tool schemas list its
+// statuses.
+func NewServer(store storage.Store, svc *service.Service, version string) *Server {
+	if version == "" {
+		version = "dev"
+	}
+
+	s := &Server{store: store, svc: svc, version: version, subscribed: make(map[string]bool)}
+	s.tools = s.toolDefs()
+	return s
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes the
+// responses to w until r is closed
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	s.outMu.Lock()
+	s.out = bufio.NewWriter(w)
+	s.outMu.Unlock()
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		resp := s.Handle(line)
+		if resp == nil {
+			continue
+		}
+		if err := s.send(resp); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	return nil
+}
+
+// send writes one message to the client
+func (s *Server) send(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	if s.out == nil {
+		return nil // Not serving
+	}
+	s.out.Write(data)
+	s.out.WriteByte('\n')
+	if err := s.out.Flush(); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// notify sends a notification to the client
+func (s *Server) notify(method string, params interface{}) error {
+	msg := struct {
+		JSONRPC string      `json:"jsonrpc"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params,omitempty"`
+	}{"2.0", method, params}
+	return s.send(msg)
+}
+
+// TasksChanged tells the client about changed task files: that the
+// resource list changed when tasks were created or deleted, and which
+// subscribed resources were updated
+func (s *Server) TasksChanged(events []watch.Event) error {
+	listChanged := false
+	var updated []string
+
+	s.subMu.Lock()
+	for _, e := range events {
+		if e.Type == watch.Created || e.Type == watch.Deleted {
+			listChanged = true
+		}
+		if uri := taskURIPrefix + e.ID; e.ID != "" && s.subscribed[uri] {
+			updated = append(updated, uri)
+		}
+	}
+	s.subMu.Unlock()
+
+	if listChanged {
+		if err := s.notify("notifications/resources/list_changed", nil); err != nil {
+			return err
+		}
+	}
+	for _, uri := range updated {
+		if err := s.notify("notifications/resources/updated", map[string]string{"uri": uri}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handle processes one JSON-RPC message and returns the response, or nil
+// for a notification
+func (s *Server) Handle(msg []byte) *Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var req Request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   errorf(codeParseError, "parse error: %v", err),
+		}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if req.IsNotification() {
+			return nil
+		}
+		return &Response{JSONRPC: "2.0", ID: req.ID, Error: errorf(codeInvalidRequest, "invalid request")}
+	}
+
+	result, rpcErr := s.dispatch(req.Method, req.Params)
+	if req.IsNotification() {
+		return nil
+	}
+
+	resp := &Response{JSONRPC: "2.0", ID: req.ID}
+	if rpcErr != nil {
+		resp.Error = rpcErr
+		return resp
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		resp.Error = errorf(codeInternalError, "failed to encode result: %v", err)
+		return resp
+	}
+	resp.Result = data
+	return resp
+}
+
+// dispatch runs a method and returns its result
+func (s *Server) dispatch(method string, params json.RawMessage) (interface{}, *Error) {
+	switch method {
+	case "initialize":
+		var p initializeParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.initialize(p), nil
+
+	case "ping":
+		return struct{}{}, nil
+
+	case "tools/list":
+		tools := make([]Tool, len(s.tools))
+		for i, t := range s.tools {
+			tools[i] = t.Tool
+		}
+		return map[string]interface{}{"tools": tools}, nil
+
+	case "tools/call":
+		var p callToolParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.callTool(p)
+
+	case "resources/list":
+		return s.listResources()
+
+	case "resources/templates/list":
+		return map[string]interface{}{"resourceTemplates": resourceTemplates}, nil
+
+	case "resources/subscribe", "resources/unsubscribe":
+		var p readResourceParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(p.URI, taskURIPrefix) {
+			return nil, errorf(codeInvalidParams, "unknown resource: %s", p.URI)
+		}
+		s.subMu.Lock()
+		if method == "resources/subscribe" {
+			s.subscribed[p.URI] = true
+		} else {
+			delete(s.subscribed, p.URI)
+		}
+		s.subMu.Unlock()
+		return struct{}{}, nil
+
+	case "resources/read":
+		var p readResourceParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.readResource(p.URI)
+
+	default:
+		return nil, errorf(codeMethodNotFound, "method not found: %s", method)
+	}
+}
+
+// initialize agrees on a protocol version: the client's if the server
+// speaks it, otherwise the newest the server knows
+func (s *Server) initialize(p initializeParams) initializeResult {
+	version := protocolVersions[0]
+	for _, v := range protocolVersions {
+		if v == p.ProtocolVersion {
+			version = v
+		}
+	}
+
+	return initializeResult{
+		ProtocolVersion: version,
+		Capabilities: map[string]interface{}{
+			"tools":     map[string]interface{}{},
+			"resources": map[string]interface{}{"subscribe": true, "listChanged": true},
+		},
+		ServerInfo: serverInfo{Name: "strand", Version: s.version},
+		Instructions: "Strand tracks tasks with dependencies. Use ready or claim to pick work, " +
+			"update_task to change status, and list_tasks with a where expression " +
+			"(e.g. 'status:in-progress AND tag:auth') to query.",
+	}
+}
+
+// decodeParams unmarshals request params, treating absent params as empty
+func decodeParams(params json.RawMessage, v interface{}) *Error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return errorf(codeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
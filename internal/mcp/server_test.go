@@ -0,0 +1,294 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/markdown"
+	"github.com/hamsa0x7/strand/internal/service"
+)
+
+// newTestHarness serves a fresh store in a temporary project
+func newTestHarness(t *testing.T) *Harness {
+	t.Helper()
+
+	core.SetWorkflow(core.DefaultWorkflow())
+	store := markdown.NewStore()
+	if err := store.Init(filepath.Join(t.TempDir(), ".strand")); err != nil {
+		t.Fatal(err)
+	}
+	svc := service.New(store, service.Options{Agent: "tester"})
+
+	h := NewHarness(NewServer(store, svc, "test"))
+	t.Cleanup(func() {
+		if err := h.Close(); err != nil {
+			t.Errorf("server failed: %v", err)
+		}
+	})
+	return h
+}
+
+// TestServer drives one project through the tools in order; each subtest
+// builds on the state the earlier ones left
+func TestServer(t *testing.T) {
+	h := newTestHarness(t)
+
+	version, err := h.Initialize()
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if version != protocolVersions[0] {
+		t.Errorf("protocol version = %q, want %q", version, protocolVersions[0])
+	}
+
+	list, err := h.ListTools()
+	if err != nil {
+		t.Fatalf("tools/list: %v", err)
+	}
+	tools := make(map[string]Tool)
+	for _, tool := range list {
+		tools[tool.Name] = tool
+	}
+	for _, name := range []string{"create_task", "update_task", "claim", "get_task"} {
+		if _, ok := tools[name]; !ok {
+			t.Fatalf("tools/list is missing %s", name)
+		}
+	}
+
+	// call runs a tool and checks its result against the output schema
+	call := func(t *testing.T, name string, args interface{}) map[string]interface{} {
+		t.Helper()
+		result, err := h.CallTool(name, args)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if result.IsError {
+			t.Fatalf("%s failed: %s", name, result.Content[0].Text)
+		}
+		out, ok := result.StructuredContent.(map[string]interface{})
+		if !ok {
+			t.Fatalf("%s: structuredContent is %T, want an object", name, result.StructuredContent)
+		}
+		if schema := tools[name].OutputSchema; schema != nil {
+			if err := checkSchema(schema, out, name); err != nil {
+				t.Errorf("%s: structuredContent does not match outputSchema: %v", name, err)
+			}
+		}
+		return out
+	}
+
+	var id string
+	ok := t.Run("create_task", func(t *testing.T) {
+		created := call(t, "create_task", service.CreateArgs{Title: "Write the harness test", Tags: []string{"mcp"}})
+		id, _ = created["id"].(string)
+		if id == "" {
+			t.Fatalf("create_task returned no id: %v", created)
+		}
+		if created["status"] != string(core.TaskStatusBacklog) {
+			t.Errorf("created status = %v, want backlog", created["status"])
+		}
+	})
+	if !ok {
+		t.FailNow()
+	}
+
+	t.Run("get_task", func(t *testing.T) {
+		got := call(t, "get_task", service.GetArgs{ID: id[:len(core.IDPrefix)+4]})
+		if got["id"] != id || got["title"] != "Write the harness test" {
+			t.Errorf("get_task by prefix = %v %v, want %s", got["id"], got["title"], id)
+		}
+	})
+
+	t.Run("update_task", func(t *testing.T) {
+		updated := call(t, "update_task", service.UpdateArgs{ID: id, Status: core.TaskStatusReady})
+		if updated["status"] != string(core.TaskStatusReady) {
+			t.Errorf("updated status = %v, want ready", updated["status"])
+		}
+	})
+
+	t.Run("claim", func(t *testing.T) {
+		claimed := call(t, "claim", service.ClaimArgs{Lease: "10m"})
+		task, ok := claimed["task"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("claim returned no task: %v", claimed)
+		}
+		if err := checkSchema(SchemaFor(core.Task{}), task, "task"); err != nil {
+			t.Errorf("claimed task does not match the task schema: %v", err)
+		}
+		if task["id"] != id || task["status"] != string(core.TaskStatusInProgress) || task["assignee"] != "tester" {
+			t.Errorf("claimed %v %v by %v, want %s in-progress by tester", task["id"], task["status"], task["assignee"], id)
+		}
+		if task["lease_expires"] == nil {
+			t.Error("claimed task has no lease")
+		}
+
+		if again := call(t, "claim", service.ClaimArgs{}); again["task"] != nil {
+			t.Errorf("second claim = %v, want no task", again["task"])
+		}
+	})
+
+	t.Run("resources/list", func(t *testing.T) {
+		resources, err := h.ListResources()
+		if err != nil {
+			t.Fatalf("resources/list: %v", err)
+		}
+		if len(resources) != 1 || resources[0].URI != taskURIPrefix+id {
+			t.Fatalf("resources/list = %v, want %s", resources, taskURIPrefix+id)
+		}
+	})
+
+	t.Run("resources/read", func(t *testing.T) {
+		contents, err := h.ReadResource(taskURIPrefix + id)
+		if err != nil {
+			t.Fatalf("resources/read: %v", err)
+		}
+		if len(contents) != 1 {
+			t.Fatalf("resources/read returned %d contents, want 1", len(contents))
+		}
+		for _, want := range []string{"status: in-progress", "assignee: tester", "# Write the harness test"} {
+			if !strings.Contains(contents[0].Text, want) {
+				t.Errorf("task file is missing %q:\n%s", want, contents[0].Text)
+			}
+		}
+	})
+}
+
+func TestServerErrors(t *testing.T) {
+	h := newTestHarness(t)
+	if _, err := h.Initialize(); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+
+	// A failing tool is reported in the result, not as a protocol error
+	result, err := h.CallTool("update_task", service.UpdateArgs{ID: "missing", Status: core.TaskStatusDone})
+	if err != nil {
+		t.Fatalf("update_task: %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("update_task on a missing task succeeded: %v", result.StructuredContent)
+	}
+
+	if _, err := h.CallTool("no_such_tool", nil); err == nil {
+		t.Error("calling an unknown tool succeeded")
+	}
+	if _, err := h.ReadResource("strand://task/missing"); err == nil {
+		t.Error("reading a missing resource succeeded")
+	}
+}
+
+// checkSchema reports the first way v, as decoded from JSON, breaks s
+func checkSchema(s *Schema, v interface{}, path string) error {
+	switch s.Type {
+	case "":
+		return nil
+
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: got %T, want an object", path, v)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required %s", path, name)
+			}
+		}
+		for name, value := range obj {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unexpected property %s", path, name)
+				}
+				continue
+			}
+			if err := checkSchema(prop, value, path+"."+name); err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: got %T, want an array", path, v)
+		}
+		for i, item := range items {
+			if err := checkSchema(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: got %T, want a string", path, v)
+		}
+		if len(s.Enum) > 0 && !containsName(s.Enum, str) {
+			return fmt.Errorf("%s: %q is not one of %v", path, str, s.Enum)
+		}
+
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s: got %v, want an integer", path, v)
+		}
+
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: got %T, want a number", path, v)
+		}
+
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: got %T, want a boolean", path, v)
+		}
+
+	default:
+		return fmt.Errorf("%s: unknown schema type %s", path, s.Type)
+	}
+	return nil
+}
+
+func containsName(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// The schema checker must itself reject mismatches, or the test above
+// proves nothing
+func TestCheckSchema(t *testing.T) {
+	schema := SchemaFor(service.TaskView{})
+
+	var valid map[string]interface{}
+	data, _ := json.Marshal(service.TaskView{
+		Task:            &core.Task{ID: "strand-1", Type: core.TaskTypeTask, Status: core.TaskStatusReady},
+		EffectiveStatus: core.TaskStatusReady,
+	})
+	if err := json.Unmarshal(data, &valid); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSchema(schema, valid, "task"); err != nil {
+		t.Fatalf("valid task rejected: %v", err)
+	}
+
+	for name, change := range map[string]func(map[string]interface{}){
+		"missing required": func(m map[string]interface{}) { delete(m, "id") },
+		"wrong type":       func(m map[string]interface{}) { m["title"] = 3.0 },
+		"bad enum":         func(m map[string]interface{}) { m["status"] = "nowhere" },
+	} {
+		task := make(map[string]interface{})
+		for k, v := range valid {
+			task[k] = v
+		}
+		change(task)
+		if checkSchema(schema, task, "task") == nil {
+			t.Errorf("%s: accepted %v", name, task)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hamsa0x7/strand/internal/service"
+)
+
+// toolDef is a tool and the handler that runs it
+type toolDef struct {
+	Tool
+	args    func() interface{} // New pointer to decode the arguments into
+	handler func(args interface{}) (interface{}, error)
+}
+
+// tasksResult wraps a list of tasks, since tool results must be objects
+type tasksResult struct {
+	Tasks interface{} `json:"tasks"`
+	Count int         `json:"count"`
+}
+
+// toolDefs lists the tools the server offers
+func (s *Server) toolDefs() []*toolDef {
+	taskSchema := SchemaFor(service.TaskView{})
+
+	return []*toolDef{
+		{
+			Tool: Tool{
+				Name:        "list_tasks",
+				Description: "List tasks, optionally filtered by a where expression, status, assignee or tag. Descriptions and comments are left out; use get_task for those.",
+				InputSchema: SchemaFor(service.ListArgs{}),
+			},
+			args: func() interface{} { return &service.ListArgs{} },
+			handler: func(a interface{}) (interface{}, error) {
+				tasks, err := s.svc.List(*a.(*service.ListArgs))
+				return tasksResult{tasks, len(tasks)}, err
+			},
+		},
+		{
+			Tool: Tool{
+				Name:         "get_task",
+				Description:  "Get a task with its description, comments, the open dependencies blocking it and the work waiting on it.",
+				InputSchema:  SchemaFor(service.GetArgs{}),
+				OutputSchema: taskSchema,
+			},
+			args: func() interface{} { return &service.GetArgs{} },
+			handler: func(a interface{}) (interface{}, error) {
+				return s.svc.Get(*a.(*service.GetArgs))
+			},
+		},
+		{
+			Tool: Tool{
+				Name:         "create_task",
+				Description:  "Create a task and return it.",
+				InputSchema:  SchemaFor(service.CreateArgs{}),
+				OutputSchema: taskSchema,
+			},
+			args: func() interface{} { return &service.CreateArgs{} },
+			handler: func(a interface{}) (interface{}, error) {
+				return s.svc.Create(*a.(*service.CreateArgs))
+			},
+		},
+		{
+			Tool: Tool{
+				Name:         "update_task",
+				Description:  "Change a task's fields. Only the fields given are changed. Closing a task may close its finished epic and free up tasks waiting on it.",
+				InputSchema:  SchemaFor(service.UpdateArgs{}),
+				OutputSchema: taskSchema,
+			},
+			args: func() interface{} { return &service.UpdateArgs{} },
+			handler: func(a interface{}) (interface{}, error) {
+				return s.svc.Update(*a.(*service.UpdateArgs), nil)
+			},
+		},
+		{
+			Tool: Tool{
+				Name:        "ready",
+				Description: "List tasks with no open dependencies, best first, ranked by priority, due date, age and how much work they unblock.",
+				InputSchema: SchemaFor(service.ReadyArgs{}),
+			},
+			args: func() interface{} { return &service.ReadyArgs{} },
+			handler: func(a interface{}) (interface{}, error) {
+				tasks, err := s.svc.Ready(*a.(*service.ReadyArgs))
+				return tasksResult{tasks, len(tasks)}, err
+			},
+		},
+		{
+			Tool: Tool{
+				Name:        "claim",
+				Description: "Atomically claim the highest-priority ready task: assign it, move it to the workflow's working status (in-progress by default) and start a lease. Returns {\"task\": null} when nothing is available.",
+				InputSchema: SchemaFor(service.ClaimArgs{}),
+			},
+			args: func() interface{} { return &service.ClaimArgs{} },
+			handler: func(a interface{}) (interface{}, error) {
+				task, _, err := s.svc.Claim(*a.(*service.ClaimArgs))
+				return map[string]interface{}{"task": task}, err
+			},
+		},
+		{
+			Tool: Tool{
+				Name:         "add_dependency",
+				Description:  "Make a task depend on another. Refused if it would create a cycle.",
+				InputSchema:  SchemaFor(service.DependencyArgs{}),
+				OutputSchema: taskSchema,
+			},
+			args: func() interface{} { return &service.DependencyArgs{} },
+			handler: func(a interface{}) (interface{}, error) {
+				return s.svc.AddDependency(*a.(*service.DependencyArgs), nil)
+			},
+		},
+		{
+			Tool: Tool{
+				Name:        "search",
+				Description: "Full-text search over task titles, tags and descriptions, best match first.",
+				InputSchema: SchemaFor(service.SearchArgs{}),
+			},
+			args: func() interface{} { return &service.SearchArgs{} },
+			handler: func(a interface{}) (interface{}, error) {
+				results, err := s.svc.Search(*a.(*service.SearchArgs))
+				return map[string]interface{}{"results": results, "count": len(results)}, err
+			},
+		},
+	}
+}
+
+// callTool runs a tool. Bad arguments and failures come back as a result
+// with isError set; only an unknown tool is a protocol error.
+func (s *Server) callTool(p callToolParams) (interface{}, *Error) {
+	var def *toolDef
+	for _, t := range s.tools {
+		if t.Name == p.Name {
+			def = t
+		}
+	}
+	if def == nil {
+		return nil, errorf(codeInvalidParams, "unknown tool: %s", p.Name)
+	}
+
+	args := def.args()
+	if len(p.Arguments) > 0 && string(p.Arguments) != "null" {
+		dec := json.NewDecoder(bytes.NewReader(p.Arguments))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(args); err != nil {
+			return toolError(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+	}
+
+	result, err := def.handler(args)
+	if err != nil {
+		return toolError(err), nil
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, errorf(codeInternalError, "failed to encode result: %v", err)
+	}
+	return &CallToolResult{
+		Content:           []Content{{Type: "text", Text: string(data)}},
+		StructuredContent: result,
+	}, nil
+}
+
+// toolError reports a failed tool call
+func toolError(err error) *CallToolResult {
+	return &CallToolResult{
+		Content: []Content{{Type: "text", Text: err.Error()}},
+		IsError: true,
+	}
+}
@@ -0,0 +1,189 @@
+// Package merge combines two versions of a task that changed the same
+// base, field by field, for strand's git merge driver
+package merge
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// TextMerger merges the changes from base to theirs into ours, reporting
+// whether conflict markers were left in the result
+type TextMerger func(base, ours, theirs string) (merged string, conflicts bool, err error)
+
+// Tasks merges ours and theirs, both changed from base, which is nil when
+// both sides added the task. A field changed on one side only takes that
+// change. A field changed on both sides takes the side updated last,
+// except for tags and dependencies, which keep the additions and removals
+// of both, comments and time log entries, which are combined, and the
+// description, which is merged as text. conflicts reports whether the
+// description has conflict markers.
+func Tasks(base, ours, theirs *core.Task, text TextMerger) (merged *core.Task, conflicts bool, err error) {
+	if base == nil {
+		base = &core.Task{}
+	}
+	oursWins := !theirs.Updated.After(ours.Updated)
+
+	m := *ours
+	m.Title = pick(base.Title, ours.Title, theirs.Title, oursWins)
+	m.Type = pick(base.Type, ours.Type, theirs.Type, oursWins)
+	m.Status = pick(base.Status, ours.Status, theirs.Status, oursWins)
+	m.Priority = pick(base.Priority, ours.Priority, theirs.Priority, oursWins)
+	m.Assignee = pick(base.Assignee, ours.Assignee, theirs.Assignee, oursWins)
+	m.Parent = pick(base.Parent, ours.Parent, theirs.Parent, oursWins)
+	m.AutoClose = pick(base.AutoClose, ours.AutoClose, theirs.AutoClose, oursWins)
+	m.Estimate = pick(base.Estimate, ours.Estimate, theirs.Estimate, oursWins)
+	m.Due = pickTime(base.Due, ours.Due, theirs.Due, oursWins)
+	m.Start = pickTime(base.Start, ours.Start, theirs.Start, oursWins)
+	m.LeaseExpires = pickTime(base.LeaseExpires, ours.LeaseExpires, theirs.LeaseExpires, oursWins)
+
+	m.Tags = mergeList(base.Tags, ours.Tags, theirs.Tags)
+	m.DependsOn = mergeList(base.DependsOn, ours.DependsOn, theirs.DependsOn)
+	m.Comments = mergeComments(ours.Comments, theirs.Comments)
+	m.TimeLog = mergeTimeLog(ours.TimeLog, theirs.TimeLog)
+
+	if !oursWins {
+		m.Updated = theirs.Updated
+	}
+	if m.Created.IsZero() || (!theirs.Created.IsZero() && theirs.Created.Before(m.Created)) {
+		m.Created = theirs.Created
+	}
+
+	switch {
+	case ours.Description == theirs.Description, theirs.Description == base.Description:
+		m.Description = ours.Description
+	case ours.Description == base.Description:
+		m.Description = theirs.Description
+	default:
+		merged, conflicted, err := text(base.Description+"\n", ours.Description+"\n", theirs.Description+"\n")
+		if err != nil {
+			return nil, false, err
+		}
+		m.Description = trimNewline(merged)
+		conflicts = conflicted
+	}
+
+	return &m, conflicts, nil
+}
+
+// pick merges a single value
+func pick[T comparable](base, ours, theirs T, oursWins bool) T {
+	switch {
+	case ours == theirs, theirs == base:
+		return ours
+	case ours == base:
+		return theirs
+	case oursWins:
+		return ours
+	default:
+		return theirs
+	}
+}
+
+// pickTime merges an optional time
+func pickTime(base, ours, theirs *time.Time, oursWins bool) *time.Time {
+	key := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	switch pick(key(base), key(ours), key(theirs), oursWins) {
+	case key(ours):
+		return ours
+	default:
+		return theirs
+	}
+}
+
+// mergeList keeps what both sides kept and what either side added, in
+// ours' order followed by theirs' additions
+func mergeList(base, ours, theirs []string) []string {
+	inBase, inOurs, inTheirs := set(base), set(ours), set(theirs)
+
+	var merged []string
+	seen := make(map[string]bool)
+	add := func(item string) {
+		if !seen[item] {
+			seen[item] = true
+			merged = append(merged, item)
+		}
+	}
+	for _, item := range ours {
+		if !inBase[item] || inTheirs[item] {
+			add(item)
+		}
+	}
+	for _, item := range theirs {
+		if !inBase[item] || inOurs[item] {
+			add(item)
+		}
+	}
+	return merged
+}
+
+// mergeComments combines both sides' comments, oldest first
+func mergeComments(ours, theirs []core.Comment) []core.Comment {
+	type key struct {
+		author, body string
+		created      int64
+	}
+
+	var merged []core.Comment
+	seen := make(map[key]bool)
+	for _, c := range append(append([]core.Comment(nil), ours...), theirs...) {
+		k := key{c.Author, c.Body, c.Created.Unix()}
+		if !seen[k] {
+			seen[k] = true
+			merged = append(merged, c)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Created.Before(merged[j].Created) })
+	return merged
+}
+
+// mergeTimeLog combines both sides' time entries, oldest first. An entry
+// for the same session stopped on one side takes the stopped version.
+func mergeTimeLog(ours, theirs []core.TimeEntry) []core.TimeEntry {
+	type key struct {
+		agent string
+		start int64
+	}
+
+	var merged []core.TimeEntry
+	index := make(map[key]int)
+	for _, e := range append(append([]core.TimeEntry(nil), ours...), theirs...) {
+		k := key{e.Agent, e.Start.Unix()}
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(merged)
+			merged = append(merged, e)
+			continue
+		}
+		if merged[i].End == nil && e.End != nil {
+			merged[i] = e
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Start.Before(merged[j].Start) })
+	return merged
+}
+
+// set makes a membership map
+func set(items []string) map[string]bool {
+	m := make(map[string]bool, len(items))
+	for _, item := range items {
+		m[item] = true
+	}
+	return m
+}
+
+// trimNewline drops the final newline added for the text merge
+func trimNewline(s string) string {
+	if len(s) > 0 && s[len(s)-1] == '\n' {
+		return s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,236 @@
+package merge
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+var (
+	t0 = time.Date(2026, 1, 17, 9, 0, 0, 0, time.UTC)
+	t1 = t0.Add(time.Hour)
+	t2 = t0.Add(2 * time.Hour)
+)
+
+// version returns a copy of base with change applied, updated at the given
+// time
+func version(base core.Task, updated time.Time, change func(*core.Task)) *core.Task {
+	task := base
+	task.Tags = append([]string(nil), base.Tags...)
+	task.DependsOn = append([]string(nil), base.DependsOn...)
+	task.Updated = updated
+	change(&task)
+	return &task
+}
+
+// noText fails any test that reaches the text merge
+func noText(base, ours, theirs string) (string, bool, error) {
+	return "", false, errors.New("unexpected text merge")
+}
+
+func TestTasks(t *testing.T) {
+	due := t0.AddDate(0, 0, 7)
+	later := t0.AddDate(0, 0, 14)
+	base := core.Task{
+		ID:          "strand-a3f9",
+		Title:       "Base",
+		Status:      core.TaskStatusReady,
+		Priority:    core.TaskPriorityMedium,
+		Description: "line one",
+		Tags:        []string{"a", "b"},
+		DependsOn:   []string{"strand-1"},
+		Due:         &due,
+		Created:     t0,
+		Updated:     t0,
+	}
+
+	tests := []struct {
+		name   string
+		ours   func(*core.Task)
+		theirs func(*core.Task)
+		oursAt time.Time // Theirs is updated at t1
+		check  func(t *testing.T, m *core.Task)
+	}{
+		{
+			name:   "one side changes a field",
+			ours:   func(*core.Task) {},
+			theirs: func(task *core.Task) { task.Title = "Theirs" },
+			oursAt: t2,
+			check: func(t *testing.T, m *core.Task) {
+				if m.Title != "Theirs" {
+					t.Errorf("title = %q, want theirs even though ours is newer", m.Title)
+				}
+			},
+		},
+		{
+			name:   "both change a field, ours newer",
+			ours:   func(task *core.Task) { task.Status = core.TaskStatusDone },
+			theirs: func(task *core.Task) { task.Status = core.TaskStatusBlocked },
+			oursAt: t2,
+			check: func(t *testing.T, m *core.Task) {
+				if m.Status != core.TaskStatusDone {
+					t.Errorf("status = %s, want done", m.Status)
+				}
+				if !m.Updated.Equal(t2) {
+					t.Errorf("updated = %v, want %v", m.Updated, t2)
+				}
+			},
+		},
+		{
+			name:   "both change a field, theirs newer",
+			ours:   func(task *core.Task) { task.Status = core.TaskStatusDone },
+			theirs: func(task *core.Task) { task.Status = core.TaskStatusBlocked },
+			oursAt: t0,
+			check: func(t *testing.T, m *core.Task) {
+				if m.Status != core.TaskStatusBlocked {
+					t.Errorf("status = %s, want blocked", m.Status)
+				}
+				if !m.Updated.Equal(t1) {
+					t.Errorf("updated = %v, want %v", m.Updated, t1)
+				}
+			},
+		},
+		{
+			name:   "different fields on each side",
+			ours:   func(task *core.Task) { task.Priority = core.TaskPriorityHigh },
+			theirs: func(task *core.Task) { task.Assignee = "bob" },
+			oursAt: t2,
+			check: func(t *testing.T, m *core.Task) {
+				if m.Priority != core.TaskPriorityHigh || m.Assignee != "bob" {
+					t.Errorf("priority, assignee = %s, %s, want high, bob", m.Priority, m.Assignee)
+				}
+			},
+		},
+		{
+			name:   "optional time moved on one side",
+			ours:   func(*core.Task) {},
+			theirs: func(task *core.Task) { task.Due = &later },
+			oursAt: t2,
+			check: func(t *testing.T, m *core.Task) {
+				if m.Due == nil || !m.Due.Equal(later) {
+					t.Errorf("due = %v, want %v", m.Due, later)
+				}
+			},
+		},
+		{
+			name:   "optional time cleared and moved",
+			ours:   func(task *core.Task) { task.Due = nil },
+			theirs: func(task *core.Task) { task.Due = &later },
+			oursAt: t2,
+			check: func(t *testing.T, m *core.Task) {
+				if m.Due != nil {
+					t.Errorf("due = %v, want cleared by the newer side", m.Due)
+				}
+			},
+		},
+		{
+			name:   "tags and dependencies keep both sides' edits",
+			ours:   func(task *core.Task) { task.Tags = []string{"a", "c"}; task.DependsOn = nil },
+			theirs: func(task *core.Task) { task.Tags = []string{"a", "b", "d"} },
+			oursAt: t2,
+			check: func(t *testing.T, m *core.Task) {
+				if want := []string{"a", "c", "d"}; !reflect.DeepEqual(m.Tags, want) {
+					t.Errorf("tags = %v, want %v", m.Tags, want)
+				}
+				if len(m.DependsOn) != 0 {
+					t.Errorf("depends_on = %v, want the removal kept", m.DependsOn)
+				}
+			},
+		},
+		{
+			name: "comments and time log combine",
+			ours: func(task *core.Task) {
+				task.Comments = []core.Comment{{Author: "alice", Created: t1, Body: "ours"}}
+				task.TimeLog = []core.TimeEntry{{Agent: "alice", Start: t0}}
+			},
+			theirs: func(task *core.Task) {
+				task.Comments = []core.Comment{{Author: "bob", Created: t0, Body: "theirs"}}
+				task.TimeLog = []core.TimeEntry{{Agent: "alice", Start: t0, End: &t1}}
+			},
+			oursAt: t2,
+			check: func(t *testing.T, m *core.Task) {
+				if len(m.Comments) != 2 || m.Comments[0].Body != "theirs" || m.Comments[1].Body != "ours" {
+					t.Errorf("comments = %+v, want theirs then ours", m.Comments)
+				}
+				if len(m.TimeLog) != 1 || m.TimeLog[0].End == nil {
+					t.Errorf("time log = %+v, want the stopped session once", m.TimeLog)
+				}
+			},
+		},
+		{
+			name:   "description changed on one side",
+			ours:   func(*core.Task) {},
+			theirs: func(task *core.Task) { task.Description = "line two" },
+			oursAt: t2,
+			check: func(t *testing.T, m *core.Task) {
+				if m.Description != "line two" {
+					t.Errorf("description = %q, want theirs", m.Description)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ours := version(base, tt.oursAt, tt.ours)
+			theirs := version(base, t1, tt.theirs)
+
+			m, conflicts, err := Tasks(&base, ours, theirs, noText)
+			if err != nil {
+				t.Fatalf("Tasks: %v", err)
+			}
+			if conflicts {
+				t.Error("conflicts reported")
+			}
+			tt.check(t, m)
+		})
+	}
+}
+
+func TestTasksDescription(t *testing.T) {
+	base := &core.Task{Description: "base", Updated: t0}
+	ours := &core.Task{Description: "ours", Updated: t1}
+	theirs := &core.Task{Description: "theirs", Updated: t1}
+
+	var got [3]string
+	text := func(b, o, th string) (string, bool, error) {
+		got = [3]string{b, o, th}
+		return "<<<\nmerged\n>>>\n", true, nil
+	}
+
+	m, conflicts, err := Tasks(base, ours, theirs, text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [3]string{"base\n", "ours\n", "theirs\n"}; got != want {
+		t.Errorf("text merge got %q, want %q", got, want)
+	}
+	if m.Description != "<<<\nmerged\n>>>" {
+		t.Errorf("description = %q, want the text merge without its final newline", m.Description)
+	}
+	if !conflicts {
+		t.Error("conflicts from the text merge were not reported")
+	}
+}
+
+func TestTasksAddedOnBothSides(t *testing.T) {
+	ours := &core.Task{ID: "strand-a3f9", Title: "Ours", Tags: []string{"x"}, Created: t1, Updated: t1}
+	theirs := &core.Task{ID: "strand-a3f9", Title: "Theirs", Tags: []string{"y"}, Created: t0, Updated: t2}
+
+	m, _, err := Tasks(nil, ours, theirs, noText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Title != "Theirs" {
+		t.Errorf("title = %q, want the newer side", m.Title)
+	}
+	if want := []string{"x", "y"}; !reflect.DeepEqual(m.Tags, want) {
+		t.Errorf("tags = %v, want %v", m.Tags, want)
+	}
+	if !m.Created.Equal(t0) {
+		t.Errorf("created = %v, want the earlier %v", m.Created, t0)
+	}
+}
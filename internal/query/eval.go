@@ -0,0 +1,181 @@
+package query
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// eval evaluates a node against a task in memory
+func eval(n Node, task *core.Task, env Env) bool {
+	switch n := n.(type) {
+	case *And:
+		return eval(n.Left, task, env) && eval(n.Right, task, env)
+	case *Or:
+		return eval(n.Left, task, env) || eval(n.Right, task, env)
+	case *Not:
+		return !eval(n.Expr, task, env)
+	case *Text:
+		return containsFold(task.Title, n.Value) || containsFold(task.Description, n.Value)
+	case *Compare:
+		return evalCompare(n, task, env)
+	default:
+		return false
+	}
+}
+
+// evalCompare evaluates a single field comparison
+func evalCompare(c *Compare, task *core.Task, env Env) bool {
+	value := c.resolveValue(env)
+
+	var match bool
+	switch fields[c.Field] {
+	case kindID:
+		match = core.MatchesIDPrefix(task.ID, value)
+
+	case kindString:
+		match = stringField(task, c.Field) == value
+
+	case kindRef:
+		if value == "none" {
+			match = task.Parent == ""
+		} else {
+			match = task.Parent != "" && core.MatchesIDPrefix(task.Parent, value)
+		}
+
+	case kindText:
+		if c.Field == "title" {
+			match = containsFold(task.Title, value)
+		} else {
+			match = containsFold(task.Title, value) || containsFold(task.Description, value)
+		}
+
+	case kindList:
+		for _, item := range listField(task, c.Field) {
+			if item == value || (c.Field == "dep" && core.MatchesIDPrefix(item, value)) {
+				match = true
+				break
+			}
+		}
+
+	case kindPriority:
+		// Lower rank is more urgent, so priority>=high means rank <= high's rank
+		have := core.PriorityRank(task.Priority)
+		want := core.PriorityRank(core.TaskPriority(value))
+		switch c.Op {
+		case OpGt:
+			return have < want
+		case OpGe:
+			return have <= want
+		case OpLt:
+			return have > want
+		case OpLe:
+			return have >= want
+		}
+		match = have == want
+
+	case kindTime:
+		t := timeField(task, c.Field)
+		if c.none {
+			match = t.IsZero()
+			break
+		}
+		if t.IsZero() {
+			// Unset optional times never compare
+			return c.Op == OpNe
+		}
+		from, to := c.timeRange(env)
+		switch c.Op {
+		case OpGt:
+			return t.After(from)
+		case OpGe:
+			return !t.Before(from)
+		case OpLt:
+			return t.Before(from)
+		case OpLe:
+			return !t.After(from)
+		}
+		match = !t.Before(from) && (to.IsZero() || t.Before(to))
+	}
+
+	if c.Op == OpNe {
+		return !match
+	}
+	return match
+}
+
+// resolveValue substitutes "me" and "none" for string fields
+func (c *Compare) resolveValue(env Env) string {
+	if c.Field == "assignee" {
+		switch c.Value {
+		case "me":
+			return env.Me
+		case "none":
+			return ""
+		}
+	}
+	return c.Value
+}
+
+// timeRange returns the instant a time comparison is anchored at and, for
+// ':' and '=' on a whole day, the end of that day
+func (c *Compare) timeRange(env Env) (from, to time.Time) {
+	if c.at.IsZero() {
+		return env.Now.Add(-c.ago), time.Time{}
+	}
+	if c.day {
+		return c.at, c.at.AddDate(0, 0, 1)
+	}
+	return c.at, time.Time{}
+}
+
+// stringField returns the value of an exact-match field
+func stringField(task *core.Task, field string) string {
+	switch field {
+	case "status":
+		return string(task.Status)
+	case "type":
+		return string(task.Type)
+	case "assignee":
+		return task.Assignee
+	default:
+		return ""
+	}
+}
+
+// listField returns the values of a list field
+func listField(task *core.Task, field string) []string {
+	switch field {
+	case "tag":
+		return task.Tags
+	case "dep":
+		return task.DependsOn
+	default:
+		return nil
+	}
+}
+
+// timeField returns the value of a time field
+func timeField(task *core.Task, field string) time.Time {
+	switch field {
+	case "created":
+		return task.Created
+	case "updated":
+		return task.Updated
+	case "due":
+		if task.Due != nil {
+			return *task.Due
+		}
+	case "start":
+		if task.Start != nil {
+			return *task.Start
+		}
+	}
+	return time.Time{}
+}
+
+// containsFold reports whether substr is in s, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,289 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+)
+
+// tokenKind identifies a lexical token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+// token is a lexical token with its position in the source
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// lex splits a query into tokens. The value after an operator runs to the
+// next space or parenthesis, so times like 2026-01-02T10:00:00Z stay whole.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	afterOp := false
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+			afterOp = false
+
+		case c == '(' && !afterOp:
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+			afterOp = false
+
+		case c == '"':
+			end := i + 1
+			var b strings.Builder
+			for end < len(src) && src[end] != '"' {
+				if src[end] == '\\' && end+1 < len(src) {
+					end++
+				}
+				b.WriteByte(src[end])
+				end++
+			}
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{tokString, b.String(), i})
+			i = end + 1
+			afterOp = false
+
+		case afterOp:
+			start := i
+			for i < len(src) && src[i] != ' ' && src[i] != '\t' && src[i] != ')' && src[i] != '(' {
+				i++
+			}
+			tokens = append(tokens, token{tokWord, src[start:i], start})
+			afterOp = false
+
+		case strings.ContainsRune(":=!<>", rune(c)):
+			start := i
+			op := string(c)
+			if i+1 < len(src) && src[i+1] == '=' && c != ':' && c != '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("expected '!=' at position %d", start)
+			}
+			i += len(op)
+			tokens = append(tokens, token{tokOp, op, start})
+			afterOp = true
+
+		default:
+			start := i
+			for i < len(src) && !strings.ContainsRune(" \t\n()\":=!<>", rune(src[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokWord, src[start:i], start})
+		}
+	}
+
+	tokens = append(tokens, token{tokEOF, "", len(src)})
+	return tokens, nil
+}
+
+// parser is a recursive-descent parser over the token stream
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses a filter expression such as
+//
+//	status:in-progress AND (tag:auth OR priority>=high) AND assignee:me AND updated>7d
+//
+// Terms next to each other are implicitly ANDed. AND binds tighter than OR.
+func Parse(src string) (*Query, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, fmt.Errorf("empty query")
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.value, tok.pos)
+	}
+
+	return &Query{Root: root}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword reports whether the next token is the given keyword
+func (p *parser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	if tok.kind != tokWord || !strings.EqualFold(tok.value, keyword) {
+		return false
+	}
+	// "or:foo" would be a field comparison, not a keyword
+	return p.tokens[p.pos+1].kind != tokOp
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.isKeyword("AND") {
+			p.next()
+		} else if tok := p.peek(); tok.kind == tokEOF || tok.kind == tokRParen || p.isKeyword("OR") {
+			return left, nil
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseNot() (Node, error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, fmt.Errorf("expected ')' at position %d", closing.pos)
+		}
+		return expr, nil
+
+	case tokString:
+		return &Text{Value: tok.value}, nil
+
+	case tokWord:
+		if p.peek().kind != tokOp {
+			return &Text{Value: tok.value}, nil
+		}
+
+		op := p.next()
+		value := p.next()
+		if value.kind != tokWord && value.kind != tokString {
+			return nil, fmt.Errorf("expected value after '%s%s' at position %d", tok.value, op.value, value.pos)
+		}
+		return newCompare(strings.ToLower(tok.value), Op(op.value), value.value)
+
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of query")
+
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.value, tok.pos)
+	}
+}
+
+// newCompare validates a comparison and resolves its value
+func newCompare(field string, op Op, value string) (*Compare, error) {
+	kind, ok := fields[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field '%s'. Valid: %s", field, strings.Join(Fields(), ", "))
+	}
+
+	c := &Compare{Field: field, Op: op, Value: value}
+
+	ordered := op == OpGt || op == OpGe || op == OpLt || op == OpLe
+	if ordered && kind != kindPriority && kind != kindTime {
+		return nil, fmt.Errorf("operator '%s' is not supported for field '%s'", op, field)
+	}
+
+	switch kind {
+	case kindPriority:
+		if core.PriorityRank(core.TaskPriority(value)) > core.PriorityRank(core.TaskPriorityLow) {
+			return nil, fmt.Errorf("invalid priority '%s'. Valid: critical, high, medium, low", value)
+		}
+
+	case kindTime:
+		if value == "none" && optionalTimes[field] {
+			if ordered {
+				return nil, fmt.Errorf("operator '%s' is not supported with 'none'", op)
+			}
+			c.none = true
+		} else if ago, err := dates.ParseAge(value); err == nil {
+			c.ago = ago
+		} else if ahead, err := dates.ParseAge(strings.TrimPrefix(value, "+")); err == nil && value[0] == '+' {
+			c.ago = -ahead
+		} else if at, err := time.Parse(time.RFC3339, value); err == nil {
+			c.at = at
+		} else if at, err := dates.Parse(value, time.Now()); err == nil {
+			c.at = at
+			c.day = true
+		} else {
+			return nil, fmt.Errorf("invalid time '%s' for field '%s': use a date (2006-01-02, friday), an age (30m, 12h, 7d, 2w) or an offset (+3d)", value, field)
+		}
+	}
+
+	return c, nil
+}
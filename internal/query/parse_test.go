@@ -0,0 +1,89 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "status:ready", want: "status:ready"},
+		{src: "STATUS:ready", want: "status:ready"},
+		{src: "status:ready tag:auth", want: "(status:ready AND tag:auth)"},
+		{src: "status:ready AND tag:auth", want: "(status:ready AND tag:auth)"},
+		{src: "a OR b AND c", want: "(a OR (b AND c))"},
+		{src: "(a OR b) AND c", want: "((a OR b) AND c)"},
+		{src: "a or b", want: "(a OR b)"},
+		{src: "NOT tag:wip", want: "NOT tag:wip"},
+		{src: "NOT NOT a", want: "NOT NOT a"},
+		{src: "priority>=high", want: "priority>=high"},
+		{src: "priority!=low", want: "priority!=low"},
+		{src: "updated>7d", want: "updated>7d"},
+		{src: "due<+3d", want: "due<+3d"},
+		{src: "due:none", want: "due:none"},
+		{src: "created>=2026-01-02T10:00:00Z", want: `created>="2026-01-02T10:00:00Z"`},
+		{src: `title:"login page"`, want: `title:"login page"`},
+		{src: `"exact phrase"`, want: `"exact phrase"`},
+		{src: `"say \"hi\""`, want: `"say \"hi\""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			q, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.src, err)
+			}
+			if got := q.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.src, got, tt.want)
+			}
+
+			// The normalized form parses back to itself
+			again, err := Parse(q.String())
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", q.String(), err)
+			}
+			if again.String() != tt.want {
+				t.Errorf("reparsed %s = %s", tt.want, again.String())
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "", want: "empty query"},
+		{src: "   ", want: "empty query"},
+		{src: "(status:ready", want: "expected ')'"},
+		{src: "status:ready)", want: `unexpected ")"`},
+		{src: "a AND", want: "unexpected end of query"},
+		{src: "NOT", want: "unexpected end of query"},
+		{src: `"open`, want: "unterminated string"},
+		{src: "status!ready", want: "expected '!='"},
+		{src: "status:", want: "expected value"},
+		{src: "color:red", want: "unknown field 'color'"},
+		{src: "or:foo", want: "unknown field 'or'"},
+		{src: "status>ready", want: "operator '>' is not supported for field 'status'"},
+		{src: "priority:urgent", want: "invalid priority 'urgent'"},
+		{src: "updated>soon", want: "invalid time 'soon'"},
+		{src: "due<none", want: "not supported with 'none'"},
+		{src: "created:none", want: "invalid time 'none'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			q, err := Parse(tt.src)
+			if err == nil {
+				t.Fatalf("Parse(%q) = %s, want an error", tt.src, q)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.src, err, tt.want)
+			}
+		})
+	}
+}
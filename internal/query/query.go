@@ -0,0 +1,160 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// Op is a comparison operator
+type Op string
+
+const (
+	OpMatch Op = ":"
+	OpEq    Op = "="
+	OpNe    Op = "!="
+	OpGt    Op = ">"
+	OpGe    Op = ">="
+	OpLt    Op = "<"
+	OpLe    Op = "<="
+)
+
+// fieldKind describes how a field's values are compared
+type fieldKind int
+
+const (
+	kindString   fieldKind = iota // Exact match
+	kindText                      // Case-insensitive substring
+	kindList                      // Membership in a list
+	kindPriority                  // Ordered by urgency
+	kindTime                      // Timestamps, absolute or relative
+	kindID                        // Task ID or unique prefix
+	kindRef                       // Reference to another task by ID prefix
+)
+
+// fields maps every query field to how it is compared
+var fields = map[string]fieldKind{
+	"id":       kindID,
+	"status":   kindString,
+	"type":     kindString,
+	"priority": kindPriority,
+	"assignee": kindString,
+	"tag":      kindList,
+	"dep":      kindList,
+	"parent":   kindRef,
+	"title":    kindText,
+	"text":     kindText,
+	"created":  kindTime,
+	"updated":  kindTime,
+	"due":      kindTime,
+	"start":    kindTime,
+}
+
+// optionalTimes are time fields a task may leave unset, matched by "none"
+var optionalTimes = map[string]bool{
+	"due":   true,
+	"start": true,
+}
+
+// Node is an element of a parsed query
+type Node interface {
+	String() string
+}
+
+// And matches when both sides match
+type And struct {
+	Left, Right Node
+}
+
+// Or matches when either side matches
+type Or struct {
+	Left, Right Node
+}
+
+// Not inverts its expression
+type Not struct {
+	Expr Node
+}
+
+// Compare tests a task field against a value, e.g. priority>=high
+type Compare struct {
+	Field string
+	Op    Op
+	Value string
+
+	// Resolved value for time fields: either an absolute time or an age
+	// relative to Env.Now; a negative age is in the future
+	at   time.Time
+	ago  time.Duration
+	day  bool // at is a whole calendar day
+	none bool // Matches an unset optional time
+}
+
+// Text matches a bare word or quoted phrase against title and description
+type Text struct {
+	Value string
+}
+
+func (n *And) String() string { return "(" + n.Left.String() + " AND " + n.Right.String() + ")" }
+func (n *Or) String() string  { return "(" + n.Left.String() + " OR " + n.Right.String() + ")" }
+func (n *Not) String() string { return "NOT " + n.Expr.String() }
+func (n *Compare) String() string {
+	return n.Field + string(n.Op) + quoteIfNeeded(n.Value)
+}
+func (n *Text) String() string { return quoteIfNeeded(n.Value) }
+
+// quoteIfNeeded quotes values containing spaces or special characters
+func quoteIfNeeded(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t()\":=!<>") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// Env supplies the context a query is evaluated in
+type Env struct {
+	// Now anchors relative times such as updated>7d
+	Now time.Time
+
+	// Me is substituted for the value "me", as in assignee:me
+	Me string
+}
+
+// Query is a parsed filter expression
+type Query struct {
+	Root Node
+}
+
+// String returns the normalized form of the query
+func (q *Query) String() string {
+	return q.Root.String()
+}
+
+// Match reports whether task satisfies the query
+func (q *Query) Match(task *core.Task, env Env) bool {
+	return eval(q.Root, task, env)
+}
+
+// Filter returns the tasks that satisfy the query, preserving order
+func (q *Query) Filter(tasks []*core.Task, env Env) []*core.Task {
+	var matches []*core.Task
+	for _, task := range tasks {
+		if q.Match(task, env) {
+			matches = append(matches, task)
+		}
+	}
+	return matches
+}
+
+// Fields returns the names of all queryable fields
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
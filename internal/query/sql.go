@@ -0,0 +1,170 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+)
+
+// SQL compiles the query to a WHERE clause over the cache's tasks,
+// task_tags and task_dependencies tables. Times are compared as UTC
+// RFC 3339 strings, which is how the cache stores them. Text matches call
+// unicode_lower, which the cache registers to fold case as Go does.
+func (q *Query) SQL(env Env) (string, []interface{}) {
+	var args []interface{}
+	where := toSQL(q.Root, env, &args)
+	return where, args
+}
+
+// toSQL compiles a node, appending bind arguments to args
+func toSQL(n Node, env Env, args *[]interface{}) string {
+	switch n := n.(type) {
+	case *And:
+		return "(" + toSQL(n.Left, env, args) + " AND " + toSQL(n.Right, env, args) + ")"
+	case *Or:
+		return "(" + toSQL(n.Left, env, args) + " OR " + toSQL(n.Right, env, args) + ")"
+	case *Not:
+		return "NOT " + toSQL(n.Expr, env, args)
+	case *Text:
+		pattern := LikePattern(n.Value)
+		*args = append(*args, pattern, pattern)
+		return `(unicode_lower(tasks.title) LIKE ? ESCAPE '\' OR unicode_lower(tasks.description) LIKE ? ESCAPE '\')`
+	case *Compare:
+		return compareSQL(n, env, args)
+	default:
+		return "0"
+	}
+}
+
+// compareSQL compiles a single field comparison
+func compareSQL(c *Compare, env Env, args *[]interface{}) string {
+	value := c.resolveValue(env)
+
+	var expr string
+	switch fields[c.Field] {
+	case kindID:
+		*args = append(*args, likePrefix(value), likePrefix(core.IDPrefix+value))
+		expr = `(tasks.id LIKE ? ESCAPE '\' OR tasks.id LIKE ? ESCAPE '\')`
+
+	case kindString:
+		*args = append(*args, value)
+		expr = fmt.Sprintf("COALESCE(tasks.%s, '') = ?", c.Field)
+
+	case kindRef:
+		if value == "none" {
+			expr = fmt.Sprintf("COALESCE(tasks.%s, '') = ''", c.Field)
+		} else {
+			*args = append(*args, likePrefix(value), likePrefix(core.IDPrefix+value))
+			expr = fmt.Sprintf(`(tasks.%s LIKE ? ESCAPE '\' OR tasks.%s LIKE ? ESCAPE '\')`, c.Field, c.Field)
+		}
+
+	case kindText:
+		pattern := LikePattern(value)
+		if c.Field == "title" {
+			*args = append(*args, pattern)
+			expr = `unicode_lower(tasks.title) LIKE ? ESCAPE '\'`
+		} else {
+			*args = append(*args, pattern, pattern)
+			expr = `(unicode_lower(tasks.title) LIKE ? ESCAPE '\' OR unicode_lower(tasks.description) LIKE ? ESCAPE '\')`
+		}
+
+	case kindList:
+		if c.Field == "tag" {
+			*args = append(*args, value)
+			expr = "EXISTS (SELECT 1 FROM task_tags WHERE task_tags.task_id = tasks.id AND task_tags.tag = ?)"
+		} else {
+			*args = append(*args, value, likePrefix(value), likePrefix(core.IDPrefix+value))
+			expr = `EXISTS (SELECT 1 FROM task_dependencies d WHERE d.task_id = tasks.id AND ` +
+				`(d.depends_on_id = ? OR d.depends_on_id LIKE ? ESCAPE '\' OR d.depends_on_id LIKE ? ESCAPE '\'))`
+		}
+
+	case kindPriority:
+		*args = append(*args, core.PriorityRank(core.TaskPriority(value)))
+		rank := priorityRankSQL()
+		// Lower rank is more urgent, so the comparison is inverted
+		switch c.Op {
+		case OpGt:
+			return rank + " < ?"
+		case OpGe:
+			return rank + " <= ?"
+		case OpLt:
+			return rank + " > ?"
+		case OpLe:
+			return rank + " >= ?"
+		}
+		expr = rank + " = ?"
+
+	case kindTime:
+		col := "tasks." + c.Field
+		if c.none {
+			expr = col + " IS NULL"
+			break
+		}
+		if optionalTimes[c.Field] && c.Op == OpNe {
+			// Unset optional times never compare, so != matches them
+			c := *c
+			c.Op = OpMatch
+			return "(" + col + " IS NULL OR NOT " + compareSQL(&c, env, args) + ")"
+		}
+		from, to := c.timeRange(env)
+		*args = append(*args, sqlTime(from))
+		switch c.Op {
+		case OpGt:
+			return col + " > ?"
+		case OpGe:
+			return col + " >= ?"
+		case OpLt:
+			return col + " < ?"
+		case OpLe:
+			return col + " <= ?"
+		}
+		if to.IsZero() {
+			expr = col + " >= ?"
+		} else {
+			*args = append(*args, sqlTime(to))
+			expr = "(" + col + " >= ? AND " + col + " < ?)"
+		}
+	}
+
+	if c.Op == OpNe {
+		return "NOT " + expr
+	}
+	return expr
+}
+
+// priorityRankSQL mirrors core.PriorityRank
+func priorityRankSQL() string {
+	var b strings.Builder
+	b.WriteString("(CASE tasks.priority")
+	for _, p := range []core.TaskPriority{
+		core.TaskPriorityCritical,
+		core.TaskPriorityHigh,
+		core.TaskPriorityMedium,
+		core.TaskPriorityLow,
+	} {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", p, core.PriorityRank(p))
+	}
+	fmt.Fprintf(&b, " ELSE %d END)", core.PriorityRank(""))
+	return b.String()
+}
+
+// sqlTime formats a time the way the cache stores it
+func sqlTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// likeEscaper escapes LIKE wildcards so values match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// LikePattern returns a case-insensitive substring LIKE pattern, with
+// wildcards escaped, to match with ESCAPE '\'
+func LikePattern(s string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(s)) + "%"
+}
+
+// likePrefix returns a prefix LIKE pattern
+func likePrefix(s string) string {
+	return likeEscaper.Replace(s) + "%"
+}
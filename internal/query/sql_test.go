@@ -0,0 +1,146 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSQL(t *testing.T) {
+	env := Env{Now: time.Date(2026, 1, 17, 12, 0, 0, 0, time.UTC), Me: "alice"}
+	rank := priorityRankSQL()
+
+	tests := []struct {
+		src   string
+		where string
+		args  []interface{}
+	}{
+		{
+			src:   "status:ready",
+			where: "COALESCE(tasks.status, '') = ?",
+			args:  []interface{}{"ready"},
+		},
+		{
+			src:   "assignee:me",
+			where: "COALESCE(tasks.assignee, '') = ?",
+			args:  []interface{}{"alice"},
+		},
+		{
+			src:   "assignee:none",
+			where: "COALESCE(tasks.assignee, '') = ?",
+			args:  []interface{}{""},
+		},
+		{
+			src:   "type!=bug",
+			where: "NOT COALESCE(tasks.type, '') = ?",
+			args:  []interface{}{"bug"},
+		},
+		{
+			src:   "id:a3f9",
+			where: `(tasks.id LIKE ? ESCAPE '\' OR tasks.id LIKE ? ESCAPE '\')`,
+			args:  []interface{}{"a3f9%", "strand-a3f9%"},
+		},
+		{
+			src:   "parent:none",
+			where: "COALESCE(tasks.parent, '') = ''",
+		},
+		{
+			src:   "tag:auth",
+			where: "EXISTS (SELECT 1 FROM task_tags WHERE task_tags.task_id = tasks.id AND task_tags.tag = ?)",
+			args:  []interface{}{"auth"},
+		},
+		{
+			src:   "title:100%_done",
+			where: `unicode_lower(tasks.title) LIKE ? ESCAPE '\'`,
+			args:  []interface{}{`%100\%\_done%`},
+		},
+		{
+			src:   "Login",
+			where: `(unicode_lower(tasks.title) LIKE ? ESCAPE '\' OR unicode_lower(tasks.description) LIKE ? ESCAPE '\')`,
+			args:  []interface{}{"%login%", "%login%"},
+		},
+		{
+			src:   "priority>=high",
+			where: rank + " <= ?",
+			args:  []interface{}{1},
+		},
+		{
+			src:   "priority<medium",
+			where: rank + " > ?",
+			args:  []interface{}{2},
+		},
+		{
+			src:   "updated>7d",
+			where: "tasks.updated > ?",
+			args:  []interface{}{"2026-01-10T12:00:00Z"},
+		},
+		{
+			src:   "due:none",
+			where: "tasks.due IS NULL",
+		},
+		{
+			src:   "created:2026-01-02T10:00:00Z",
+			where: "tasks.created >= ?",
+			args:  []interface{}{"2026-01-02T10:00:00Z"},
+		},
+		{
+			src:   "status:ready AND (tag:a OR NOT tag:b)",
+			where: "(COALESCE(tasks.status, '') = ? AND (EXISTS (SELECT 1 FROM task_tags WHERE task_tags.task_id = tasks.id AND task_tags.tag = ?) OR NOT EXISTS (SELECT 1 FROM task_tags WHERE task_tags.task_id = tasks.id AND task_tags.tag = ?)))",
+			args:  []interface{}{"ready", "a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			q, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.src, err)
+			}
+			where, args := q.SQL(env)
+			if where != tt.where {
+				t.Errorf("where = %s\nwant    %s", where, tt.where)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestSQLDay(t *testing.T) {
+	q, err := Parse("due:2026-02-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, to := q.Root.(*Compare).timeRange(Env{})
+
+	// A calendar day covers the whole day, whatever the local zone
+	where, args := q.SQL(Env{})
+	if want := "(tasks.due >= ? AND tasks.due < ?)"; where != want {
+		t.Errorf("where = %s, want %s", where, want)
+	}
+	if want := []interface{}{sqlTime(from), sqlTime(to)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+	if to.Sub(from) < 23*time.Hour {
+		t.Errorf("day range %v to %v is shorter than a day", from, to)
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "abc", want: "%abc%"},
+		{in: "ABC", want: "%abc%"},
+		{in: "50%", want: `%50\%%`},
+		{in: "a_b", want: `%a\_b%`},
+		{in: `C:\dir`, want: `%c:\\dir%`},
+	}
+
+	for _, tt := range tests {
+		if got := LikePattern(tt.in); got != tt.want {
+			t.Errorf("LikePattern(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/graph"
+	"github.com/hamsa0x7/strand/internal/history"
+)
+
+// closeFollowUps closes finished epics above a task that was just closed,
+// then frees up whatever the closed tasks were blocking. It does nothing
+// for an open task.
+func (s *Service) closeFollowUps(task *core.Task) error {
+	if !core.IsClosed(task.Status) {
+		return nil
+	}
+
+	closed, err := s.autoCloseParents(task)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range append([]*core.Task{task}, closed...) {
+		if err := s.promoteDependents(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// autoCloseParents moves auto-close ancestors of task to the workflow's
+// done status once all of their children are closed, walking up the
+// hierarchy. Ancestors the workflow does not allow to close are left alone.
+func (s *Service) autoCloseParents(task *core.Task) ([]*core.Task, error) {
+	if task.Parent == "" {
+		return nil, nil
+	}
+
+	taskMap, err := s.taskMap()
+	if err != nil {
+		return nil, err
+	}
+	taskMap[task.ID] = task
+
+	workflow := core.CurrentWorkflow()
+
+	var closed []*core.Task
+	for id := task.Parent; id != ""; {
+		parent, ok := taskMap[id]
+		if !ok || !parent.AutoClose || workflow.IsClosed(parent.Status) {
+			break
+		}
+		if !graph.AllChildrenClosed(taskMap, id) {
+			break
+		}
+		if workflow.CheckTransition(parent, workflow.Done, taskMap) != nil {
+			break
+		}
+
+		// Re-read for an up-to-date version before writing
+		parent, err := s.store.Get(id)
+		if err != nil {
+			return closed, err
+		}
+		before := history.Snapshot(parent)
+		parent.SetStatus(workflow.Done)
+		if _, err := s.save(s.opts.Agent, history.ActionAutoClose, before, parent); err != nil {
+			return closed, fmt.Errorf("failed to close %s: %w", parent.ID, err)
+		}
+
+		closed = append(closed, parent)
+		taskMap[id] = parent
+		id = parent.Parent
+	}
+
+	return closed, nil
+}
+
+// promoteDependents moves the backlog and blocked dependents of a closed
+// task to the workflow's ready status once none of their dependencies are
+// open, if the workflow has auto_ready on. Dependents the workflow does not
+// allow to move are left alone.
+func (s *Service) promoteDependents(task *core.Task) error {
+	workflow := core.CurrentWorkflow()
+	if !workflow.AutoReady || !workflow.IsClosed(task.Status) {
+		return nil
+	}
+
+	taskMap, err := s.taskMap()
+	if err != nil {
+		return err
+	}
+	taskMap[task.ID] = task
+
+	for _, dep := range graph.DirectDependents(taskMap, task.ID) {
+		switch dep.Status {
+		case core.TaskStatusBacklog, core.TaskStatusBlocked, workflow.Initial:
+		default:
+			continue
+		}
+		if len(dep.OpenDependencies(taskMap)) > 0 {
+			continue
+		}
+		if workflow.CheckTransition(dep, workflow.Ready, taskMap) != nil {
+			continue
+		}
+
+		// Re-read for an up-to-date version before writing
+		fresh, err := s.store.Get(dep.ID)
+		if err != nil {
+			return err
+		}
+		before := history.Snapshot(fresh)
+		fresh.SetStatus(workflow.Ready)
+		if _, err := s.save(s.opts.Agent, history.ActionAutoReady, before, fresh); err != nil {
+			return fmt.Errorf("failed to promote %s: %w", fresh.ID, err)
+		}
+		taskMap[fresh.ID] = fresh
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package service implements the task operations every front end shares,
+// the CLI, the TUI and the MCP and HTTP servers, on top of a storage.Store
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/history"
+	"github.com/hamsa0x7/strand/internal/storage"
+)
+
+var (
+	// ErrInvalid is wrapped by errors caused by bad arguments, which would
+	// fail the same way for any task
+	ErrInvalid = errors.New("invalid request")
+
+	// ErrConflict is wrapped by errors for changes the current state of
+	// the project does not allow, such as a dependency cycle or a status
+	// move the workflow refuses
+	ErrConflict = errors.New("conflict")
+)
+
+// Options configure a Service. Every hook is optional.
+type Options struct {
+	Agent   string            // Acts as this agent for history and claims
+	Weights core.ScoreWeights // Ranks the ready queue
+
+	// Lock serializes a write with other strand processes and returns
+	// the function that releases it
+	Lock func() (func(), error)
+
+	// Record appends events to the task history
+	Record func(events ...history.Event)
+
+	// Commit runs at the end of every write, after its follow-ups and
+	// while still holding the lock, e.g. to commit the files it changed
+	Commit func()
+
+	// DryRun passes the changes updates would make to Record without
+	// saving them
+	DryRun bool
+}
+
+// Service runs task operations against a store
+type Service struct {
+	store storage.Store
+	opts  Options
+}
+
+// New creates a service over store
+func New(store storage.Store, opts Options) *Service {
+	if opts.Agent == "" {
+		opts.Agent = core.CurrentAgent()
+	}
+	return &Service{store: store, opts: opts}
+}
+
+// Agent returns the agent the service acts as
+func (s *Service) Agent() string {
+	return s.opts.Agent
+}
+
+// write runs fn while holding the project lock, then commits what it
+// changed, even if it failed partway
+func (s *Service) write(fn func() error) error {
+	if s.opts.Lock != nil {
+		release, err := s.opts.Lock()
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+	err := fn()
+	if s.opts.Commit != nil {
+		s.opts.Commit()
+	}
+	return err
+}
+
+// save writes task and records how it differs from before, unless nothing
+// changed or it is a dry run
+func (s *Service) save(actor, action string, before, task *core.Task) (saved bool, err error) {
+	now := time.Now()
+	events := history.Diff(actor, action, before, task, now)
+	if len(events) == 0 {
+		return false, nil
+	}
+	if s.opts.DryRun {
+		s.record(events...)
+		return false, nil
+	}
+
+	stamp(before, task, now)
+	if err := s.store.Update(task); err != nil {
+		return false, fmt.Errorf("failed to update task: %w", err)
+	}
+	s.record(events...)
+	return true, nil
+}
+
+// stamp sets task's updated time to now. The file keeps whole seconds, so
+// it moves on by at least one to always look newer, e.g. to the merge driver.
+func stamp(before, task *core.Task, now time.Time) {
+	task.Updated = now.Truncate(time.Second)
+	if !task.Updated.After(before.Updated) {
+		task.Updated = before.Updated.Truncate(time.Second).Add(time.Second)
+	}
+}
+
+// record appends events to the history, if the service records it
+func (s *Service) record(events ...history.Event) {
+	if s.opts.Record != nil && len(events) > 0 {
+		s.opts.Record(events...)
+	}
+}
+
+// kindError is an error that matches ErrInvalid or ErrConflict with
+// errors.Is while keeping its own message
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string   { return e.err.Error() }
+func (e *kindError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// invalidf returns an error matching ErrInvalid
+func invalidf(format string, args ...interface{}) error {
+	return &kindError{kind: ErrInvalid, err: fmt.Errorf(format, args...)}
+}
+
+// conflictf returns an error matching ErrConflict
+func conflictf(format string, args ...interface{}) error {
+	return &kindError{kind: ErrConflict, err: fmt.Errorf(format, args...)}
+}
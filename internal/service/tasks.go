@@ -0,0 +1,963 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/hamsa0x7/strand/internal/graph"
+	"github.com/hamsa0x7/strand/internal/history"
+	"github.com/hamsa0x7/strand/internal/query"
+	"github.com/hamsa0x7/strand/internal/storage"
+)
+
+// DefaultLease is how long a claim lasts unless the caller asks otherwise
+const DefaultLease = 30 * time.Minute
+
+// createAttempts is how many fresh IDs Create tries before giving up on a
+// run of collisions
+const createAttempts = 3
+
+// Operation arguments, decoded from tool calls and request bodies. Field
+// descriptions come from the desc tags.
+type (
+	ListArgs struct {
+		Where    string          `json:"where,omitempty" desc:"Filter expression, e.g. 'status:in-progress AND (tag:auth OR priority>=high) AND updated>7d'"`
+		Status   core.TaskStatus `json:"status,omitempty" desc:"Only tasks in this status"`
+		Assignee string          `json:"assignee,omitempty" desc:"Only tasks assigned to this agent"`
+		Tag      string          `json:"tag,omitempty" desc:"Only tasks with this tag"`
+		Limit    int             `json:"limit,omitempty" desc:"Return at most this many tasks"`
+	}
+
+	GetArgs struct {
+		ID string `json:"id" desc:"Task ID or unique ID prefix"`
+	}
+
+	CreateArgs struct {
+		Title       string            `json:"title" desc:"Task title"`
+		Description string            `json:"description,omitempty" desc:"Markdown body"`
+		Type        core.TaskType     `json:"type,omitempty" desc:"Task type (default task)"`
+		Priority    core.TaskPriority `json:"priority,omitempty" desc:"Priority (default medium)"`
+		Assignee    string            `json:"assignee,omitempty"`
+		Tags        []string          `json:"tags,omitempty"`
+		Parent      string            `json:"parent,omitempty" desc:"Epic or task this one belongs to"`
+		AutoClose   bool              `json:"auto_close,omitempty" desc:"Close it once all of its children are done"`
+		DependsOn   []string          `json:"depends_on,omitempty" desc:"IDs of tasks that must finish first"`
+		Due         string            `json:"due,omitempty" desc:"Due date: today, tomorrow, friday, +3d, +2w or YYYY-MM-DD"`
+		Start       string            `json:"start,omitempty" desc:"Start date, in the same forms as due"`
+		Estimate    string            `json:"estimate,omitempty" desc:"Expected effort, e.g. 4h or 1h30m"`
+	}
+
+	UpdateArgs struct {
+		ID          string            `json:"id" desc:"Task ID or unique ID prefix"`
+		Title       string            `json:"title,omitempty"`
+		Description *string           `json:"description,omitempty" desc:"Replaces the markdown body"`
+		Status      core.TaskStatus   `json:"status,omitempty" desc:"New status; the project workflow may refuse the move"`
+		Priority    core.TaskPriority `json:"priority,omitempty"`
+		Assignee    string            `json:"assignee,omitempty" desc:"New assignee, or none to unassign"`
+		Parent      string            `json:"parent,omitempty" desc:"New epic or task this one belongs to, or none to detach it"`
+		AutoClose   *bool             `json:"auto_close,omitempty" desc:"Close it once all of its children are done"`
+		AddTags     []string          `json:"add_tags,omitempty" desc:"Tags to add; given with remove_tags, they take the place of the first removed tag"`
+		RemoveTags  []string          `json:"remove_tags,omitempty"`
+		Due         string            `json:"due,omitempty" desc:"Due date as for create_task, or none to clear"`
+		Start       string            `json:"start,omitempty" desc:"Start date as for create_task, or none to clear"`
+		Estimate    string            `json:"estimate,omitempty" desc:"Expected effort, or none to clear"`
+	}
+
+	ReadyArgs struct {
+		Assignee string `json:"assignee,omitempty" desc:"Only tasks assigned to this agent, or none for unassigned"`
+		Tag      string `json:"tag,omitempty" desc:"Only tasks with this tag"`
+		Limit    int    `json:"limit,omitempty" desc:"Return at most this many tasks"`
+	}
+
+	ClaimArgs struct {
+		Agent string `json:"agent,omitempty" desc:"Agent name (default: the server's agent)"`
+		Lease string `json:"lease,omitempty" desc:"Lease length, e.g. 30m (default 30m); renew it with strand heartbeat"`
+	}
+
+	ReleaseArgs struct {
+		ID    string `json:"id" desc:"Task ID or unique ID prefix"`
+		Agent string `json:"agent,omitempty" desc:"Agent holding the claim (default: the server's agent)"`
+		Force bool   `json:"force,omitempty" desc:"Release it even if another agent holds the claim"`
+	}
+
+	HeartbeatArgs struct {
+		ID    string `json:"id" desc:"Task ID or unique ID prefix"`
+		Agent string `json:"agent,omitempty" desc:"Agent holding the claim (default: the server's agent)"`
+		Lease string `json:"lease,omitempty" desc:"New lease length from now (default 30m)"`
+	}
+
+	DependencyArgs struct {
+		TaskID    string `json:"task_id" desc:"Task that must wait"`
+		DependsOn string `json:"depends_on" desc:"Task that must finish first"`
+	}
+
+	SearchArgs struct {
+		Query string `json:"query" desc:"Words, \"quoted phrases\" or prefix* to find in titles, tags and descriptions"`
+		Limit int    `json:"limit,omitempty" desc:"Return at most this many results"`
+	}
+)
+
+// TaskView is a task with its derived dependency state
+type TaskView struct {
+	*core.Task
+	EffectiveStatus core.TaskStatus `json:"effective_status"`
+	BlockedBy       []string        `json:"blocked_by,omitempty"`
+	Impact          *graph.Impact   `json:"impact,omitempty"`
+}
+
+// RankedTask is a ready task with its score
+type RankedTask struct {
+	*core.Task
+	Score    float64  `json:"score"`
+	Unblocks []string `json:"unblocks"`
+}
+
+// SearchResult is one search match
+type SearchResult struct {
+	ID      string          `json:"id"`
+	Title   string          `json:"title"`
+	Status  core.TaskStatus `json:"status"`
+	Score   float64         `json:"score,omitempty"`
+	Snippet string          `json:"snippet,omitempty"`
+}
+
+// Precondition checks the current version of a task before it is changed,
+// e.g. against an If-Match header
+type Precondition func(current *core.Task) error
+
+// List returns the tasks passing the filters, without descriptions or
+// comments
+func (s *Service) List(args ListArgs) ([]TaskView, error) {
+	if args.Status != "" {
+		if err := validateStatus(args.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	tasks, err := s.where(args.Where)
+	if err != nil {
+		return nil, err
+	}
+	taskMap, err := s.taskMap()
+	if err != nil {
+		return nil, err
+	}
+
+	views := []TaskView{}
+	for _, task := range tasks {
+		if args.Limit > 0 && len(views) == args.Limit {
+			break
+		}
+		switch {
+		case args.Status != "" && task.Status != args.Status:
+		case args.Assignee != "" && task.Assignee != args.Assignee:
+		case args.Tag != "" && !contains(task.Tags, args.Tag):
+		default:
+			summary := *task
+			summary.Description = ""
+			summary.Comments = nil
+			views = append(views, viewTask(&summary, taskMap))
+		}
+	}
+
+	return views, nil
+}
+
+// Get returns a task with its description, comments, open dependencies
+// and the work waiting on it
+func (s *Service) Get(args GetArgs) (TaskView, error) {
+	if args.ID == "" {
+		return TaskView{}, invalidf("id is required")
+	}
+	task, err := s.store.Get(args.ID)
+	if err != nil {
+		return TaskView{}, err
+	}
+	taskMap, err := s.taskMap()
+	if err != nil {
+		return TaskView{}, err
+	}
+
+	view := viewTask(task, taskMap)
+	impact := graph.ComputeImpact(taskMap, task.ID)
+	view.Impact = &impact
+	return view, nil
+}
+
+// Create creates a task
+func (s *Service) Create(args CreateArgs) (TaskView, error) {
+	if strings.TrimSpace(args.Title) == "" {
+		return TaskView{}, invalidf("title is required")
+	}
+	if args.Type == "" {
+		args.Type = core.TaskTypeTask
+	}
+	if err := validateType(args.Type); err != nil {
+		return TaskView{}, err
+	}
+	if args.Priority != "" {
+		if err := validatePriority(args.Priority); err != nil {
+			return TaskView{}, err
+		}
+	}
+
+	task, err := core.NewTask(args.Title, args.Type)
+	if err != nil {
+		return TaskView{}, err
+	}
+	// As the file will keep them, so the task matches what a reload reads
+	task.Created = task.Created.Truncate(time.Second)
+	task.Updated = task.Created
+	task.Description = args.Description
+	if args.Priority != "" {
+		task.Priority = args.Priority
+	}
+	task.Assignee = args.Assignee
+	task.AutoClose = args.AutoClose
+	if len(args.Tags) > 0 {
+		task.Tags = args.Tags
+	}
+
+	if task.Due, err = parseDay("due", args.Due); err != nil {
+		return TaskView{}, err
+	}
+	if task.Start, err = parseDay("start", args.Start); err != nil {
+		return TaskView{}, err
+	}
+	if task.Estimate, err = parseEstimate(args.Estimate); err != nil {
+		return TaskView{}, err
+	}
+
+	err = s.write(func() error {
+		if args.Parent != "" {
+			parentID, err := s.checkParent(task, args.Parent)
+			if err != nil {
+				return err
+			}
+			task.Parent = parentID
+		}
+		for _, depID := range args.DependsOn {
+			dep, err := s.store.Get(depID)
+			if err != nil {
+				return invalidf("dependency task not found: %v", err)
+			}
+			if !contains(task.DependsOn, dep.ID) {
+				task.DependsOn = append(task.DependsOn, dep.ID)
+			}
+		}
+
+		// Another process may have taken the ID; retry with a fresh one
+		for attempt := 1; ; attempt++ {
+			err := s.store.Create(task)
+			if err == nil {
+				break
+			}
+			if !errors.Is(err, storage.ErrExists) || attempt == createAttempts {
+				return fmt.Errorf("failed to create task: %w", err)
+			}
+			if task.ID, err = core.NewID(task.Title); err != nil {
+				return err
+			}
+		}
+		s.record(history.Created(s.opts.Agent, task))
+		return nil
+	})
+	if err != nil {
+		return TaskView{}, err
+	}
+
+	return s.view(task)
+}
+
+// Update changes the fields given in args. check, if not nil, may refuse
+// the change after seeing the current version of the task.
+func (s *Service) Update(args UpdateArgs, check Precondition) (TaskView, error) {
+	if args.ID == "" {
+		return TaskView{}, invalidf("id is required")
+	}
+	if args.Status != "" {
+		if err := validateStatus(args.Status); err != nil {
+			return TaskView{}, err
+		}
+	}
+	if args.Priority != "" {
+		if err := validatePriority(args.Priority); err != nil {
+			return TaskView{}, err
+		}
+	}
+	due, err := parseDay("due", args.Due)
+	if err != nil {
+		return TaskView{}, err
+	}
+	start, err := parseDay("start", args.Start)
+	if err != nil {
+		return TaskView{}, err
+	}
+	estimate, err := parseEstimate(args.Estimate)
+	if err != nil {
+		return TaskView{}, err
+	}
+
+	var task *core.Task
+	err = s.write(func() error {
+		task, err = s.store.Get(args.ID)
+		if err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(task); err != nil {
+				return err
+			}
+		}
+		before := history.Snapshot(task)
+
+		if args.Status != "" && args.Status != task.Status {
+			taskMap, err := s.taskMap()
+			if err != nil {
+				return err
+			}
+			if err := core.CurrentWorkflow().CheckTransition(task, args.Status, taskMap); err != nil {
+				return conflictf("%v", err)
+			}
+			task.SetStatus(args.Status)
+		}
+		if args.Title != "" {
+			task.Title = args.Title
+		}
+		if args.Description != nil {
+			task.Description = *args.Description
+		}
+		if args.Priority != "" {
+			task.Priority = args.Priority
+		}
+		if args.Assignee == "none" {
+			task.Assignee = ""
+		} else if args.Assignee != "" {
+			task.Assignee = args.Assignee
+		}
+		if args.Parent == "none" {
+			task.Parent = ""
+		} else if args.Parent != "" {
+			parentID, err := s.checkParent(task, args.Parent)
+			if err != nil {
+				return err
+			}
+			task.Parent = parentID
+		}
+		if args.AutoClose != nil {
+			task.AutoClose = *args.AutoClose
+		}
+		if len(args.AddTags) > 0 || len(args.RemoveTags) > 0 {
+			task.Tags = editTags(task.Tags, args.AddTags, args.RemoveTags)
+		}
+		if args.Due != "" {
+			task.Due = due
+		}
+		if args.Start != "" {
+			task.Start = start
+		}
+		if args.Estimate != "" {
+			task.Estimate = estimate
+		}
+
+		saved, err := s.save(s.opts.Agent, history.ActionUpdate, before, task)
+		if err != nil || !saved {
+			return err
+		}
+		return s.closeFollowUps(task)
+	})
+	if err != nil {
+		return TaskView{}, err
+	}
+
+	return s.view(task)
+}
+
+// Delete removes a task. check, if not nil, may refuse after seeing the
+// current version of the task.
+func (s *Service) Delete(id string, check Precondition) error {
+	return s.write(func() error {
+		task, err := s.store.Get(id)
+		if err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(task); err != nil {
+				return err
+			}
+		}
+
+		if err := s.store.Delete(task.ID); err != nil {
+			return fmt.Errorf("failed to delete task: %w", err)
+		}
+		s.record(history.Deleted(s.opts.Agent, task, time.Now()))
+		return nil
+	})
+}
+
+// Ready returns the tasks with no open dependencies, best first
+func (s *Service) Ready(args ReadyArgs) ([]RankedTask, error) {
+	tasks, err := s.store.Ready()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ready tasks: %w", err)
+	}
+	taskMap, err := s.taskMap()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ranked := []RankedTask{}
+	for _, task := range tasks {
+		switch {
+		case args.Assignee == "none" && task.Assignee != "":
+		case args.Assignee != "" && args.Assignee != "none" && task.Assignee != args.Assignee:
+		case args.Tag != "" && !contains(task.Tags, args.Tag):
+		default:
+			impact := graph.ComputeImpact(taskMap, task.ID)
+			ranked = append(ranked, RankedTask{
+				Task:     task,
+				Score:    s.opts.Weights.Score(task.Factors(now, impact.Blocks)),
+				Unblocks: impact.Unblocks,
+			})
+		}
+	}
+
+	// Highest score first, then the oldest task
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created)
+		}
+		return a.ID < b.ID
+	})
+	if args.Limit > 0 && len(ranked) > args.Limit {
+		ranked = ranked[:args.Limit]
+	}
+
+	return ranked, nil
+}
+
+// Claim atomically assigns the highest-priority claimable task to an
+// agent, moves it to the workflow's working status and starts a lease. It
+// returns nil when nothing can be claimed. Expired leases are released
+// first; reclaimed lists those tasks as they were, with the assignee that
+// lost the lease.
+func (s *Service) Claim(args ClaimArgs) (claimed *core.Task, reclaimed []*core.Task, err error) {
+	agent := args.Agent
+	if agent == "" {
+		agent = s.opts.Agent
+	}
+	lease := DefaultLease
+	if args.Lease != "" {
+		d, err := dates.ParseDuration(args.Lease)
+		if err != nil {
+			return nil, nil, invalidf("%v", err)
+		}
+		lease = d
+	}
+
+	if err := core.CurrentWorkflow().CanClaim(); err != nil {
+		return nil, nil, conflictf("%v", err)
+	}
+
+	err = s.write(func() error {
+		now := time.Now()
+		tasks, err := s.store.List()
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+		taskMap := graph.TaskMap(tasks)
+
+		// Return expired claims to the pool first
+		for _, task := range tasks {
+			if !task.LeaseExpired(now) || core.IsClosed(task.Status) {
+				continue
+			}
+			released, err := s.store.Get(task.ID)
+			if err != nil {
+				return err
+			}
+			before := history.Snapshot(released)
+			released.Release(now)
+			if _, err := s.save(s.opts.Agent, history.ActionRelease, before, released); err != nil {
+				return fmt.Errorf("failed to reclaim task %s: %w", task.ID, err)
+			}
+			taskMap[released.ID] = released
+			reclaimed = append(reclaimed, task)
+		}
+
+		var candidates []*core.Task
+		for _, task := range taskMap {
+			if task.IsClaimable(taskMap, now) && task.HasStarted(now) {
+				candidates = append(candidates, task)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		// Highest priority first, then oldest
+		sort.SliceStable(candidates, func(i, j int) bool {
+			ri, rj := core.PriorityRank(candidates[i].Priority), core.PriorityRank(candidates[j].Priority)
+			if ri != rj {
+				return ri < rj
+			}
+			if !candidates[i].Created.Equal(candidates[j].Created) {
+				return candidates[i].Created.Before(candidates[j].Created)
+			}
+			return candidates[i].ID < candidates[j].ID
+		})
+
+		// Re-read the file: cached tasks do not carry comments
+		task, err := s.store.Get(candidates[0].ID)
+		if err != nil {
+			return err
+		}
+		before := history.Snapshot(task)
+		task.Claim(agent, lease, now)
+		if _, err := s.save(agent, history.ActionClaim, before, task); err != nil {
+			return fmt.Errorf("failed to claim task: %w", err)
+		}
+		claimed = task
+		return nil
+	})
+	if err != nil {
+		return nil, reclaimed, err
+	}
+
+	return claimed, reclaimed, nil
+}
+
+// Release drops the agent's claim on a task and returns it to the
+// workflow's ready status
+func (s *Service) Release(args ReleaseArgs) (*core.Task, error) {
+	if args.ID == "" {
+		return nil, invalidf("id is required")
+	}
+	agent := args.Agent
+	if agent == "" {
+		agent = s.opts.Agent
+	}
+
+	var task *core.Task
+	err := s.write(func() error {
+		var err error
+		task, err = s.store.Get(args.ID)
+		if err != nil {
+			return err
+		}
+		if !task.HasLease() {
+			return conflictf("task %s is not claimed", task.ID)
+		}
+		if task.Assignee != agent && !args.Force {
+			return conflictf("task %s is claimed by %s, not %s (force releases it anyway)", task.ID, task.Assignee, agent)
+		}
+
+		before := history.Snapshot(task)
+		task.Release(time.Now())
+		if _, err := s.save(agent, history.ActionRelease, before, task); err != nil {
+			return fmt.Errorf("failed to release task: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Heartbeat renews the lease on a task the agent has claimed
+func (s *Service) Heartbeat(args HeartbeatArgs) (*core.Task, error) {
+	if args.ID == "" {
+		return nil, invalidf("id is required")
+	}
+	agent := args.Agent
+	if agent == "" {
+		agent = s.opts.Agent
+	}
+	lease := DefaultLease
+	if args.Lease != "" {
+		d, err := dates.ParseDuration(args.Lease)
+		if err != nil {
+			return nil, invalidf("%v", err)
+		}
+		lease = d
+	}
+
+	var task *core.Task
+	err := s.write(func() error {
+		var err error
+		task, err = s.store.Get(args.ID)
+		if err != nil {
+			return err
+		}
+		if !task.HasLease() {
+			return conflictf("task %s is not claimed", task.ID)
+		}
+		if task.Assignee != agent {
+			return conflictf("task %s is claimed by %s, not %s", task.ID, task.Assignee, agent)
+		}
+
+		now := time.Now()
+		before := history.Snapshot(task)
+		task.Renew(lease, now)
+		stamp(before, task, now)
+		if err := s.store.Update(task); err != nil {
+			return fmt.Errorf("failed to renew lease: %w", err)
+		}
+		s.record(history.Renewed(agent, before, task, now))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// AddDependency makes a task depend on another, refusing cycles
+func (s *Service) AddDependency(args DependencyArgs, check Precondition) (TaskView, error) {
+	if args.TaskID == "" || args.DependsOn == "" {
+		return TaskView{}, invalidf("task_id and depends_on are required")
+	}
+
+	var task *core.Task
+	err := s.write(func() error {
+		var err error
+		task, err = s.store.Get(args.TaskID)
+		if err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(task); err != nil {
+				return err
+			}
+		}
+		dep, err := s.store.Get(args.DependsOn)
+		if err != nil {
+			return invalidf("dependency task not found: %v", err)
+		}
+		if dep.ID == task.ID {
+			return conflictf("cannot create circular dependency: task cannot depend on itself")
+		}
+		if contains(task.DependsOn, dep.ID) {
+			return conflictf("task already depends on %s", dep.ID)
+		}
+
+		taskMap, err := s.taskMap()
+		if err != nil {
+			return err
+		}
+		if cycle := graph.WouldCycle(taskMap, task.ID, dep.ID); cycle != nil {
+			return conflictf("cannot create circular dependency: %s", graph.FormatPath(cycle))
+		}
+
+		before := history.Snapshot(task)
+		task.DependsOn = append(task.DependsOn, dep.ID)
+		_, err = s.save(s.opts.Agent, history.ActionUpdate, before, task)
+		return err
+	})
+	if err != nil {
+		return TaskView{}, err
+	}
+
+	return s.view(task)
+}
+
+// RemoveDependency drops a dependency. The dependency may be given by a
+// prefix of the ID in the task's own list, so references to deleted
+// tasks can still be removed.
+func (s *Service) RemoveDependency(args DependencyArgs, check Precondition) (TaskView, error) {
+	if args.TaskID == "" || args.DependsOn == "" {
+		return TaskView{}, invalidf("task_id and depends_on are required")
+	}
+
+	var task *core.Task
+	err := s.write(func() error {
+		var err error
+		task, err = s.store.Get(args.TaskID)
+		if err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(task); err != nil {
+				return err
+			}
+		}
+
+		var matches []string
+		for _, dep := range task.DependsOn {
+			if core.MatchesIDPrefix(dep, args.DependsOn) {
+				matches = append(matches, dep)
+			}
+		}
+		switch {
+		case len(matches) == 0:
+			return conflictf("task does not depend on %s", args.DependsOn)
+		case len(matches) > 1:
+			return &storage.AmbiguousIDError{Prefix: args.DependsOn, Candidates: matches}
+		}
+
+		before := history.Snapshot(task)
+		kept := []string{}
+		for _, dep := range task.DependsOn {
+			if dep != matches[0] {
+				kept = append(kept, dep)
+			}
+		}
+		task.DependsOn = kept
+		_, err = s.save(s.opts.Agent, history.ActionUpdate, before, task)
+		return err
+	})
+	if err != nil {
+		return TaskView{}, err
+	}
+
+	return s.view(task)
+}
+
+// Dependencies returns the tasks id depends on. References to deleted
+// tasks are left out.
+func (s *Service) Dependencies(id string) ([]*core.Task, error) {
+	task, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	taskMap, err := s.taskMap()
+	if err != nil {
+		return nil, err
+	}
+
+	deps := []*core.Task{}
+	for _, depID := range task.DependsOn {
+		if dep, ok := taskMap[depID]; ok {
+			deps = append(deps, dep)
+		}
+	}
+	return deps, nil
+}
+
+// Dependents returns the tasks that depend on id, directly or with
+// transitive also through other tasks
+func (s *Service) Dependents(id string, transitive bool) ([]*core.Task, error) {
+	task, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	taskMap, err := s.taskMap()
+	if err != nil {
+		return nil, err
+	}
+
+	dependents := graph.DirectDependents(taskMap, task.ID)
+	if transitive {
+		dependents = graph.TransitiveDependents(taskMap, task.ID)
+	}
+	if dependents == nil {
+		dependents = []*core.Task{}
+	}
+	return dependents, nil
+}
+
+// Search runs a full-text search, best match first
+func (s *Service) Search(args SearchArgs) ([]SearchResult, error) {
+	if strings.TrimSpace(args.Query) == "" {
+		return nil, invalidf("query is required")
+	}
+
+	var hits []*storage.SearchHit
+	if searcher, ok := s.store.(storage.Searcher); ok {
+		var err error
+		hits, err = searcher.Search(args.Query)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+	} else {
+		tasks, err := s.store.List()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tasks: %w", err)
+		}
+		needle := strings.ToLower(args.Query)
+		for _, task := range tasks {
+			if strings.Contains(strings.ToLower(task.Title), needle) ||
+				strings.Contains(strings.ToLower(task.Description), needle) {
+				hits = append(hits, &storage.SearchHit{Task: task})
+			}
+		}
+	}
+
+	results := []SearchResult{}
+	for _, hit := range hits {
+		if args.Limit > 0 && len(results) == args.Limit {
+			break
+		}
+		results = append(results, SearchResult{hit.ID, hit.Title, hit.Status, hit.Score, hit.Snippet})
+	}
+	return results, nil
+}
+
+// where returns the tasks matching a query expression, or all tasks
+func (s *Service) where(expr string) ([]*core.Task, error) {
+	if expr == "" {
+		return s.store.List()
+	}
+
+	q, err := query.Parse(expr)
+	if err != nil {
+		return nil, invalidf("invalid where expression: %v", err)
+	}
+	env := query.Env{Now: time.Now(), Me: s.opts.Agent}
+	if querier, ok := s.store.(storage.Querier); ok {
+		return querier.Where(q, env)
+	}
+
+	tasks, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return q.Filter(tasks, env), nil
+}
+
+// checkParent resolves parentID and checks that it can become task's
+// parent without making the hierarchy a cycle
+func (s *Service) checkParent(task *core.Task, parentID string) (string, error) {
+	parent, err := s.store.Get(parentID)
+	if err != nil {
+		return "", invalidf("parent task not found: %v", err)
+	}
+	taskMap, err := s.taskMap()
+	if err != nil {
+		return "", err
+	}
+	if cycle := graph.ParentCycle(taskMap, task.ID, parent.ID); cycle != nil {
+		return "", conflictf("cannot create hierarchy cycle: %s", graph.FormatPath(cycle))
+	}
+	return parent.ID, nil
+}
+
+// editTags removes and adds tags. Added tags take the place of the first
+// removed one the task had, so renaming a tag keeps its position; tags
+// that are both added and removed are kept.
+func editTags(tags, add, remove []string) []string {
+	edited := []string{}
+	keep := func(tag string) {
+		if !contains(edited, tag) {
+			edited = append(edited, tag)
+		}
+	}
+
+	placed := false
+	for _, tag := range tags {
+		if !contains(remove, tag) {
+			keep(tag)
+			continue
+		}
+		if !placed {
+			for _, a := range add {
+				keep(a)
+			}
+			placed = true
+		}
+	}
+	if !placed {
+		for _, a := range add {
+			keep(a)
+		}
+	}
+	return edited
+}
+
+// taskMap loads every task keyed by ID
+func (s *Service) taskMap() (map[string]*core.Task, error) {
+	tasks, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	return graph.TaskMap(tasks), nil
+}
+
+// view adds the derived dependency state to a task just written
+func (s *Service) view(task *core.Task) (TaskView, error) {
+	taskMap, err := s.taskMap()
+	if err != nil {
+		return TaskView{}, err
+	}
+	taskMap[task.ID] = task
+	return viewTask(task, taskMap), nil
+}
+
+func viewTask(task *core.Task, taskMap map[string]*core.Task) TaskView {
+	return TaskView{
+		Task:            task,
+		EffectiveStatus: task.EffectiveStatus(taskMap),
+		BlockedBy:       task.OpenDependencies(taskMap),
+	}
+}
+
+func validateStatus(status core.TaskStatus) error {
+	workflow := core.CurrentWorkflow()
+	if !workflow.IsValid(status) {
+		return invalidf("invalid status '%s'. Valid: %s", status, workflow.StatusList())
+	}
+	return nil
+}
+
+func validatePriority(priority core.TaskPriority) error {
+	switch priority {
+	case core.TaskPriorityCritical, core.TaskPriorityHigh, core.TaskPriorityMedium, core.TaskPriorityLow:
+		return nil
+	default:
+		return invalidf("invalid priority '%s'. Valid: critical, high, medium, low", priority)
+	}
+}
+
+func validateType(taskType core.TaskType) error {
+	switch taskType {
+	case core.TaskTypeTask, core.TaskTypeEpic, core.TaskTypeBug, core.TaskTypeStory:
+		return nil
+	default:
+		return invalidf("invalid type '%s'. Valid: task, epic, bug, story", taskType)
+	}
+}
+
+// parseDay parses a due or start argument; "" leaves it unset and "none"
+// clears it
+func parseDay(name, value string) (*time.Time, error) {
+	if value == "" || value == "none" {
+		return nil, nil
+	}
+	day, err := dates.Parse(value, time.Now())
+	if err != nil {
+		return nil, invalidf("invalid %s: %v", name, err)
+	}
+	return &day, nil
+}
+
+// parseEstimate parses an estimate argument; "" and "none" mean no estimate
+func parseEstimate(value string) (core.Duration, error) {
+	if value == "" || value == "none" {
+		return 0, nil
+	}
+	d, err := dates.ParseDuration(value)
+	if err != nil {
+		return 0, invalidf("invalid estimate: %v", err)
+	}
+	return core.Duration(d), nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
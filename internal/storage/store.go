@@ -1,9 +1,41 @@
 package storage
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/query"
 )
 
+// ErrNotFound is returned when no task matches a requested ID
+var ErrNotFound = errors.New("task not found")
+
+// ErrExists is returned when creating a task whose ID is already taken
+var ErrExists = errors.New("task already exists")
+
+// AmbiguousIDError is returned when an ID prefix matches more than one task
+type AmbiguousIDError struct {
+	Prefix     string
+	Candidates []string
+}
+
+func (e *AmbiguousIDError) Error() string {
+	return fmt.Sprintf("ambiguous task ID '%s' matches %d tasks: %s",
+		e.Prefix, len(e.Candidates), strings.Join(e.Candidates, ", "))
+}
+
+// ConflictError is returned when a task changed on disk after it was read,
+// so writing it back would discard someone else's changes
+type ConflictError struct {
+	ID string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("task %s was modified by another process since it was read; reload and retry", e.ID)
+}
+
 // Store defines the interface for task storage operations
 type Store interface {
 	// Init initializes the storage system
@@ -12,13 +44,17 @@ type Store interface {
 	// Create creates a new task
 	Create(task *core.Task) error
 	
-	// Get retrieves a task by ID
+	// Get retrieves a task by ID or unique ID prefix
 	Get(id string) (*core.Task, error)
+
+	// Resolve expands an ID or unique ID prefix to a full task ID
+	Resolve(prefix string) (string, error)
 	
 	// List retrieves all tasks
 	List() ([]*core.Task, error)
 	
-	// Update updates an existing task
+	// Update updates an existing task, returning a *ConflictError if it
+	// changed on disk since it was read
 	Update(task *core.Task) error
 	
 	// Delete deletes a task
@@ -30,3 +66,43 @@ type Store interface {
 	// Close closes the storage
 	Close() error
 }
+
+// SearchHit is a task matched by a text search
+type SearchHit struct {
+	*core.Task
+	Score   float64 `json:"score"`             // Relevance; higher is better
+	Snippet string  `json:"snippet,omitempty"` // Excerpt around the match
+}
+
+// Searcher is implemented by stores that can search task text natively
+type Searcher interface {
+	// Search returns tasks whose title, description or tags match query,
+	// best match first
+	Search(query string) ([]*SearchHit, error)
+}
+
+// TagCount is the number of tasks carrying a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagCounter is implemented by stores that can count tags natively
+type TagCounter interface {
+	// TagCounts returns every tag in use, most used first
+	TagCounts() ([]TagCount, error)
+}
+
+// Refresher is implemented by stores that cache task files, to pick up
+// files changed outside strand right away
+type Refresher interface {
+	// Refresh brings the cache up to date with the files
+	Refresh() error
+}
+
+// Querier is implemented by stores that can evaluate query expressions
+// natively instead of filtering every task in memory
+type Querier interface {
+	// Where returns tasks matching q
+	Where(q *query.Query, env query.Env) ([]*core.Task, error)
+}
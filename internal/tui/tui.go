@@ -3,10 +3,14 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/dates"
+	"github.com/hamsa0x7/strand/internal/graph"
+	"github.com/hamsa0x7/strand/internal/query"
 	"github.com/hamsa0x7/strand/internal/storage"
 )
 
@@ -20,6 +24,16 @@ var (
 			Foreground(lipgloss.Color("170")).
 			Bold(true)
 
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9"))
+
+	overdueStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")).
+			Bold(true)
+
+	dueSoonStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11"))
+
 	statusColors = map[core.TaskStatus]lipgloss.Color{
 		core.TaskStatusDone:       lipgloss.Color("10"),
 		core.TaskStatusInProgress: lipgloss.Color("12"),
@@ -30,31 +44,206 @@ var (
 	}
 )
 
+// StatusFunc moves a task to a status, enforcing the workflow, and saves
+// it along with whatever the change sets off
+type StatusFunc func(id string, status core.TaskStatus) error
+
+// TagColorFunc returns the color for a tag as an ANSI number or #rrggbb,
+// or "" for none
+type TagColorFunc func(tag string) string
+
+// TasksChangedMsg tells the TUI that task files changed on disk, e.g.
+// from the CLI, an editor or a git pull, so it reloads them
+type TasksChangedMsg struct {
+	Deleted []string // IDs of tasks whose files were removed
+}
+
 type model struct {
-	tasks    []*core.Task
-	cursor   int
-	selected map[string]bool
-	store    storage.Store
-	width    int
-	height   int
+	allTasks  []*core.Task
+	tasks     []*core.Task // allTasks narrowed by the filter
+	cursor    int
+	selected  map[string]bool
+	store     storage.Store
+	setStatus StatusFunc
+	tagColor  TagColorFunc
+	width     int
+	height    int
+
+	// Query filter, edited with '/'
+	filter      *query.Query
+	filtering   bool
+	filterInput string
+	filterErr   string
+
+	// Status change, started with 's'
+	choosingStatus bool
+	statusChoices  []core.TaskStatus
+	message        string
+	messageErr     bool
+
+	// Detail view of one task, opened with enter
+	detail       *core.Task
+	detailScroll int
 }
 
-func InitialModel(store storage.Store) (model, error) {
+// InitialModel creates the TUI model. Status changes are saved through
+// setStatus; tagColor, if not nil, colors tags.
+func InitialModel(store storage.Store, setStatus StatusFunc, tagColor TagColorFunc) (model, error) {
 	tasks, err := store.List()
 	if err != nil {
 		return model{}, err
 	}
 
 	return model{
-		tasks:    tasks,
-		cursor:   0,
-		selected: make(map[string]bool),
-		store:    store,
-		width:    80,
-		height:   24,
+		allTasks:  tasks,
+		tasks:     tasks,
+		cursor:    0,
+		selected:  make(map[string]bool),
+		store:     store,
+		setStatus: setStatus,
+		tagColor:  tagColor,
+		width:     80,
+		height:    24,
 	}, nil
 }
 
+// reload reads the task list again, keeping the filter
+func (m *model) reload() {
+	tasks, err := m.store.List()
+	if err == nil {
+		m.allTasks = tasks
+		m.applyFilter()
+	}
+}
+
+// applyFilter recomputes the visible tasks from allTasks
+func (m *model) applyFilter() {
+	if m.filter == nil {
+		m.tasks = m.allTasks
+	} else {
+		env := query.Env{Now: time.Now(), Me: core.CurrentAgent()}
+		m.tasks = m.filter.Filter(m.allTasks, env)
+	}
+
+	if m.cursor >= len(m.tasks) {
+		m.cursor = len(m.tasks) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// updateFilterInput handles keys while the filter prompt is open
+func (m model) updateFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.filtering = false
+		m.filterErr = ""
+
+	case tea.KeyEnter:
+		if strings.TrimSpace(m.filterInput) == "" {
+			m.filter = nil
+		} else {
+			q, err := query.Parse(m.filterInput)
+			if err != nil {
+				m.filterErr = err.Error()
+				return m, nil
+			}
+			m.filter = q
+		}
+		m.filtering = false
+		m.filterErr = ""
+		m.applyFilter()
+
+	case tea.KeyBackspace:
+		if len(m.filterInput) > 0 {
+			runes := []rune(m.filterInput)
+			m.filterInput = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeySpace:
+		m.filterInput += " "
+
+	case tea.KeyRunes:
+		m.filterInput += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+// startStatusChange offers the statuses the selected task may move to
+func (m *model) startStatusChange() {
+	if len(m.tasks) == 0 {
+		return
+	}
+
+	task := m.tasks[m.cursor]
+	workflow := core.CurrentWorkflow()
+
+	m.statusChoices = nil
+	for _, status := range workflow.Statuses {
+		if status != task.Status && workflow.CanTransition(task.Status, status) {
+			m.statusChoices = append(m.statusChoices, status)
+		}
+	}
+
+	if len(m.statusChoices) == 0 {
+		m.setMessage(fmt.Sprintf("%s cannot move from %s", task.ID, task.Status), true)
+		return
+	}
+	m.choosingStatus = true
+}
+
+// updateStatusChoice handles keys while the status picker is open
+func (m model) updateStatusChoice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.choosingStatus = false
+
+	case tea.KeyRunes:
+		if len(msg.Runes) != 1 {
+			return m, nil
+		}
+		n := int(msg.Runes[0] - '1')
+		if n < 0 || n >= len(m.statusChoices) {
+			return m, nil
+		}
+		m.choosingStatus = false
+		m.changeStatus(m.tasks[m.cursor], m.statusChoices[n])
+	}
+
+	return m, nil
+}
+
+// changeStatus moves a task to status; setStatus enforces the workflow's
+// guards against the file's current version
+func (m *model) changeStatus(task *core.Task, status core.TaskStatus) {
+	if err := m.setStatus(task.ID, status); err != nil {
+		m.setMessage(err.Error(), true)
+		return
+	}
+
+	m.setMessage(fmt.Sprintf("✅ %s → %s", task.ID, status), false)
+
+	tasks, err := m.store.List()
+	if err == nil {
+		m.allTasks = tasks
+		m.applyFilter()
+	}
+}
+
+// setMessage sets the status line shown below the task list
+func (m *model) setMessage(msg string, isErr bool) {
+	m.message = msg
+	m.messageErr = isErr
+}
+
 func (m model) Init() tea.Cmd {
 	return nil
 }
@@ -66,11 +255,51 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case TasksChangedMsg:
+		m.reload()
+		if m.detail != nil {
+			id := m.detail.ID
+			if task, err := m.store.Get(id); err == nil {
+				m.detail = task
+			} else {
+				for _, deleted := range msg.Deleted {
+					if deleted == id {
+						m.detail = nil
+						m.setMessage(fmt.Sprintf("Task %s was deleted", id), true)
+					}
+				}
+			}
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilterInput(msg)
+		}
+		if m.choosingStatus {
+			return m.updateStatusChoice(msg)
+		}
+		if m.detail != nil {
+			return m.updateDetail(msg)
+		}
+		m.message = ""
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
 
+		case "/":
+			m.filtering = true
+			if m.filter != nil {
+				m.filterInput = m.filter.String()
+			}
+
+		case "esc":
+			// Clear the filter
+			m.filter = nil
+			m.filterInput = ""
+			m.applyFilter()
+
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
@@ -81,43 +310,196 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor++
 			}
 
-		case "enter", " ":
+		case " ":
 			if len(m.tasks) > 0 {
 				id := m.tasks[m.cursor].ID
 				m.selected[id] = !m.selected[id]
 			}
 
-		case "r":
-			// Refresh task list
-			tasks, err := m.store.List()
-			if err == nil {
-				m.tasks = tasks
-				if m.cursor >= len(m.tasks) {
-					m.cursor = len(m.tasks) - 1
-				}
-				if m.cursor < 0 {
-					m.cursor = 0
+		case "enter":
+			if len(m.tasks) > 0 {
+				// Read the file for the full task, including comments
+				task, err := m.store.Get(m.tasks[m.cursor].ID)
+				if err != nil {
+					m.setMessage(err.Error(), true)
+				} else {
+					m.detail = task
+					m.detailScroll = 0
 				}
 			}
+
+		case "s":
+			m.startStatusChange()
+
+		case "r":
+			m.reload()
 		}
 	}
 
 	return m, nil
 }
 
+// updateDetail handles keys while the detail view is open
+func (m model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "esc", "enter", "backspace":
+		m.detail = nil
+
+	case "up", "k":
+		if m.detailScroll > 0 {
+			m.detailScroll--
+		}
+
+	case "down", "j":
+		if m.detailScroll < len(m.detailLines())-m.detailHeight() {
+			m.detailScroll++
+		}
+	}
+
+	return m, nil
+}
+
+// detailHeight is the number of detail lines that fit on screen, leaving
+// room for the title and help
+func (m model) detailHeight() int {
+	if h := m.height - 6; h > 0 {
+		return h
+	}
+	return 1
+}
+
+// detailLines renders the full task with its comments, one entry per line
+func (m model) detailLines() []string {
+	task := m.detail
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	taskMap := graph.TaskMap(m.allTasks)
+
+	var lines []string
+	field := func(label, value string) {
+		if value != "" {
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("%-10s", label))+" "+value)
+		}
+	}
+
+	field("ID", task.ID)
+	field("Type", string(task.Type))
+	status := statusLabel(task, taskMap)
+	if open := task.OpenDependencies(taskMap); len(open) > 0 && !core.IsClosed(task.Status) {
+		status += fmt.Sprintf(" (%s, waiting on %s)", task.Status, strings.Join(open, ", "))
+	}
+	field("Status", status)
+	field("Priority", string(task.Priority))
+	field("Assignee", task.Assignee)
+	field("Parent", task.Parent)
+	field("Tags", m.tagLabels(task.Tags))
+	field("Depends", strings.Join(task.DependsOn, ", "))
+	if task.Due != nil {
+		field("Due", dueLabel(task, time.Now()))
+	}
+	if task.Start != nil {
+		field("Start", dates.Format(*task.Start))
+	}
+	field("Updated", task.Updated.Format("2006-01-02 15:04"))
+
+	if task.Description != "" {
+		lines = append(lines, "")
+		lines = append(lines, strings.Split(task.Description, "\n")...)
+	}
+
+	if len(task.Comments) > 0 {
+		lines = append(lines, "", titleStyle.Render(fmt.Sprintf("Comments (%d)", len(task.Comments))))
+		for _, c := range task.Comments {
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("── %s, %s", c.Author, c.Created.Format("2006-01-02 15:04"))))
+			lines = append(lines, strings.Split(c.Body, "\n")...)
+			lines = append(lines, "")
+		}
+	}
+
+	return lines
+}
+
+// detailView renders the scrolled detail view
+func (m model) detailView() string {
+	lines := m.detailLines()
+
+	scroll := m.detailScroll
+	if max := len(lines) - m.detailHeight(); scroll > max {
+		scroll = max
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	end := scroll + m.detailHeight()
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(m.detail.Title))
+	b.WriteString("\n\n")
+	b.WriteString(strings.Join(lines[scroll:end], "\n"))
+	b.WriteString("\n\n")
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	b.WriteString(helpStyle.Render("↑/k: scroll up • ↓/j: scroll down • esc: back • q: quit"))
+
+	return b.String()
+}
+
+// dueLabel renders a task's due date, red when overdue and yellow when due
+// within two days
+func dueLabel(task *core.Task, now time.Time) string {
+	if task.Due == nil {
+		return ""
+	}
+
+	label := dates.Format(*task.Due)
+	switch {
+	case task.IsOverdue(now):
+		return overdueStyle.Render(label + " (overdue)")
+	case task.IsDueWithin(now, 2):
+		return dueSoonStyle.Render(label)
+	default:
+		return label
+	}
+}
+
 func (m model) View() string {
+	if m.detail != nil {
+		return m.detailView()
+	}
+
 	var b strings.Builder
 
 	// Title
 	b.WriteString(titleStyle.Render("Strand Task Manager"))
 	b.WriteString("\n\n")
 
+	// Filter prompt or active filter
+	if m.filtering {
+		b.WriteString("Filter: " + m.filterInput + "█\n")
+		if m.filterErr != "" {
+			b.WriteString(errorStyle.Render(m.filterErr) + "\n")
+		}
+		b.WriteString("\n")
+	} else if m.filter != nil {
+		b.WriteString(fmt.Sprintf("Filter: %s (%d/%d)\n\n", m.filter.String(), len(m.tasks), len(m.allTasks)))
+	}
+
 	if len(m.tasks) == 0 {
+		if m.filter != nil {
+			b.WriteString("No tasks match the filter. Press '/' to edit or esc to clear.\n")
+			return b.String()
+		}
 		b.WriteString("No tasks found. Press 'q' to quit.\n")
 		return b.String()
 	}
 
 	// Task list
+	now := time.Now()
+	taskMap := graph.TaskMap(m.allTasks)
 	for i, task := range m.tasks {
 		cursor := " "
 		if i == m.cursor {
@@ -129,17 +511,19 @@ func (m model) View() string {
 			checked = "x"
 		}
 
-		// Status color
-		statusColor := statusColors[task.Status]
-		statusStyle := lipgloss.NewStyle().Foreground(statusColor)
-
 		line := fmt.Sprintf("%s [%s] %s - %s [%s]",
 			cursor,
 			checked,
-			statusStyle.Render(string(task.Status)),
+			statusLabel(task, taskMap),
 			task.Title,
 			task.Priority,
 		)
+		if task.Due != nil {
+			line += " due " + dueLabel(task, now)
+		}
+		if len(task.Tags) > 0 {
+			line += " " + m.tagLabels(task.Tags)
+		}
 
 		if i == m.cursor {
 			line = selectedStyle.Render(line)
@@ -149,11 +533,55 @@ func (m model) View() string {
 		b.WriteString("\n")
 	}
 
+	// Status picker or last result
+	if m.choosingStatus {
+		b.WriteString("\nMove to: ")
+		for i, status := range m.statusChoices {
+			statusStyle := lipgloss.NewStyle().Foreground(statusColors[status])
+			b.WriteString(fmt.Sprintf("%d) %s  ", i+1, statusStyle.Render(string(status))))
+		}
+		b.WriteString("(esc: cancel)\n")
+	} else if m.message != "" {
+		b.WriteString("\n")
+		if m.messageErr {
+			b.WriteString(errorStyle.Render(m.message))
+		} else {
+			b.WriteString(m.message)
+		}
+		b.WriteString("\n")
+	}
+
 	// Help text
 	b.WriteString("\n")
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	help := helpStyle.Render("↑/k: up • ↓/j: down • space: select • r: refresh • q: quit")
+	help := helpStyle.Render("↑/k: up • ↓/j: down • enter: details • space: select • s: status • /: filter • esc: clear filter • r: refresh • q: quit")
 	b.WriteString(help)
 
 	return b.String()
 }
+
+// tagLabels renders tags in their configured colors, comma-separated
+func (m model) tagLabels(tags []string) string {
+	labels := make([]string, len(tags))
+	for i, tag := range tags {
+		labels[i] = tag
+		if m.tagColor == nil {
+			continue
+		}
+		if color := m.tagColor(tag); color != "" {
+			labels[i] = lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(tag)
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+// statusLabel renders a task's effective status in its color. A task
+// blocked only by open dependencies shows as "blocked*".
+func statusLabel(task *core.Task, taskMap map[string]*core.Task) string {
+	status := task.EffectiveStatus(taskMap)
+	label := string(status)
+	if task.IsDependencyBlocked(taskMap) {
+		label += "*"
+	}
+	return lipgloss.NewStyle().Foreground(statusColors[status]).Render(label)
+}
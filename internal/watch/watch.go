@@ -0,0 +1,242 @@
+// Package watch follows changes to task files, whoever makes them: strand
+// itself, an editor or a git pull. It polls the tasks directory, so it
+// needs no platform file notification support.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hamsa0x7/strand/internal/core"
+	"github.com/hamsa0x7/strand/internal/history"
+)
+
+// EventType says what happened to a task file
+type EventType string
+
+const (
+	Created EventType = "created"
+	Updated EventType = "updated"
+	Deleted EventType = "deleted"
+	Invalid EventType = "invalid" // The file no longer parses, e.g. mid-edit or after a conflicted merge
+)
+
+// Event is a change to one task file
+type Event struct {
+	Type    EventType  `json:"type"`
+	ID      string     `json:"id,omitempty"`
+	Path    string     `json:"path"`
+	Time    time.Time  `json:"time"`
+	Task    *core.Task `json:"task,omitempty"`    // The new version, or the removed task for Deleted
+	Changes []string   `json:"changes,omitempty"` // What changed, for Updated
+	Error   string     `json:"error,omitempty"`   // Why the file does not parse, for Invalid
+}
+
+// Options configure a Watcher
+type Options struct {
+	// Interval is how often the directory is polled (default 250ms)
+	Interval time.Duration
+
+	// Debounce is how long the files must stay unchanged before their
+	// changes are reported, so a burst of writes is one batch (default
+	// 200ms)
+	Debounce time.Duration
+
+	// Load parses a task file. Required.
+	Load func(path string) (*core.Task, error)
+
+	// Refresh, if set, runs before each batch is reported, e.g. to bring
+	// the SQLite cache up to date
+	Refresh func() error
+}
+
+// Watcher reports changes to the task files in a directory
+type Watcher struct {
+	dir   string
+	opts  Options
+	files map[string]*file
+}
+
+// file is what the watcher last saw of a task file
+type file struct {
+	stat
+	task *core.Task // Last version that parsed, if any
+}
+
+// stat identifies a version of a file
+type stat struct {
+	mtime int64
+	size  int64
+}
+
+// New creates a watcher over the task files in dir
+func New(dir string, opts Options) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = 250 * time.Millisecond
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = 200 * time.Millisecond
+	}
+	return &Watcher{dir: dir, opts: opts}
+}
+
+// Run reports each batch of changes to handle until ctx is done. Files
+// present when it starts are not reported.
+func (w *Watcher) Run(ctx context.Context, handle func([]Event)) error {
+	current, err := w.scan()
+	if err != nil {
+		return err
+	}
+	w.files = make(map[string]*file, len(current))
+	for path, st := range current {
+		f := &file{stat: st}
+		f.task, _ = w.opts.Load(path)
+		w.files[path] = f
+	}
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	last := current
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := w.scan()
+		if err != nil {
+			return err
+		}
+		if !sameStats(current, last) {
+			last = current
+			changedAt = time.Now()
+			continue
+		}
+		if changedAt.IsZero() || time.Since(changedAt) < w.opts.Debounce {
+			continue
+		}
+		changedAt = time.Time{}
+
+		events := w.apply(current)
+		if len(events) == 0 {
+			continue
+		}
+		if w.opts.Refresh != nil {
+			if err := w.opts.Refresh(); err != nil {
+				return fmt.Errorf("failed to refresh cache: %w", err)
+			}
+		}
+		handle(events)
+	}
+}
+
+// scan stats every task file in the directory
+func (w *Watcher) scan() (map[string]stat, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks directory: %w", err)
+	}
+
+	stats := make(map[string]stat, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Removed while listing
+		}
+		stats[filepath.Join(w.dir, entry.Name())] = stat{info.ModTime().UnixNano(), info.Size()}
+	}
+	return stats, nil
+}
+
+// apply re-reads the files that changed since they were last seen and
+// returns the resulting events, sorted by path
+func (w *Watcher) apply(current map[string]stat) []Event {
+	now := time.Now()
+	var events []Event
+
+	for path, st := range current {
+		f, known := w.files[path]
+		if known && f.stat == st {
+			continue
+		}
+		if !known {
+			f = &file{}
+			w.files[path] = f
+		}
+		f.stat = st
+
+		task, err := w.opts.Load(path)
+		if err != nil {
+			event := Event{Type: Invalid, Path: path, Time: now, Error: err.Error()}
+			if f.task != nil {
+				event.ID = f.task.ID
+			}
+			events = append(events, event)
+			continue
+		}
+
+		before := f.task
+		f.task = task
+		switch {
+		case before == nil:
+			events = append(events, Event{Type: Created, ID: task.ID, Path: path, Time: now, Task: task})
+		default:
+			changes := describeChanges(before, task)
+			if len(changes) == 0 && task.Updated.Equal(before.Updated) {
+				continue // Touched, not changed
+			}
+			events = append(events, Event{Type: Updated, ID: task.ID, Path: path, Time: now, Task: task, Changes: changes})
+		}
+	}
+
+	for path, f := range w.files {
+		if _, ok := current[path]; ok {
+			continue
+		}
+		delete(w.files, path)
+		event := Event{Type: Deleted, Path: path, Time: now, Task: f.task}
+		if f.task != nil {
+			event.ID = f.task.ID
+		}
+		events = append(events, event)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
+
+// describeChanges lists the fields that differ, as the history shows them
+func describeChanges(before, after *core.Task) []string {
+	var changes []string
+	for _, e := range history.Diff("", history.ActionEdit, before, after, time.Time{}) {
+		changes = append(changes, e.String())
+	}
+	if n := len(after.Comments) - len(before.Comments); n > 0 {
+		changes = append(changes, fmt.Sprintf("comments: +%d", n))
+	}
+	return changes
+}
+
+// sameStats reports whether two scans saw the same files
+func sameStats(a, b map[string]stat) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, st := range a {
+		if b[path] != st {
+			return false
+		}
+	}
+	return true
+}